@@ -25,10 +25,231 @@ var (
 	IntentStatusTypeSolver     = "Solver"
 	IntentStatusTypeOffloading = "NamespaceOffloading"
 	IntentStatusTypeDeploy     = "Deploy"
+	// IntentStatusTypeNetworkPolicy reports the translation of the security constraints into NetworkPolicy objects,
+	// and whether these are enforced on the offloaded Pods.
+	IntentStatusTypeNetworkPolicy = "NetworkPolicy"
+	// IntentStatusTypeTerminating tracks the teardown of the allocated resources upon the Intent deletion.
+	IntentStatusTypeTerminating = "Terminating"
+	// IntentStatusTypeMigration tracks the allocation of a new Solver generation upon a resources or constraints change.
+	IntentStatusTypeMigration = "Migration"
+	// IntentStatusTypeWorkload reports the lifecycle of the workload Job or Deployment.
+	IntentStatusTypeWorkload = "Workload"
+	// IntentStatusTypeBudget tracks the accrued cost against the maximum total cost of the Intent.
+	IntentStatusTypeBudget = "Budget"
+	// IntentStatusTypeDeadline reports the admission of the batch Intent upon its deadline.
+	IntentStatusTypeDeadline = "Deadline"
+	// IntentStatusTypeAvailability reports whether the workload is running in one of its availability windows.
+	IntentStatusTypeAvailability = "Availability"
+	// IntentStatusTypeStorage reports the binding of the PersistentVolumeClaims backing the persistent volumes.
+	IntentStatusTypeStorage = "Storage"
 )
 
+var (
+	IntentWorkloadReasonPending   = "Pending"
+	IntentWorkloadReasonRunning   = "Running"
+	IntentWorkloadReasonSucceeded = "Succeeded"
+	IntentWorkloadReasonFailed    = "Failed"
+)
+
+var (
+	IntentBudgetReasonWithin = "WithinBudget"
+	// IntentBudgetReasonWarning is reported once the accrued cost crosses one of the warning thresholds.
+	IntentBudgetReasonWarning = "BudgetThresholdReached"
+	// IntentBudgetReasonExceeded is reported when the budget is exhausted, and the workload has been suspended.
+	IntentBudgetReasonExceeded = "BudgetExceeded"
+)
+
+var (
+	IntentDeadlineReasonScheduled = "DeadlineScheduled"
+	// IntentDeadlineReasonExceeded is reported when the deadline has passed, or it cannot be met given the Job timeout.
+	IntentDeadlineReasonExceeded = "DeadlineExceeded"
+)
+
+var (
+	IntentAvailabilityReasonWindowOpen = "WindowOpen"
+	// IntentAvailabilityReasonWindowClosed is reported when the workload is held, or suspended, until the next window.
+	IntentAvailabilityReasonWindowClosed = "WindowClosed"
+	// IntentAvailabilityReasonNoWindow is reported when no window opens within the evaluation horizon.
+	IntentAvailabilityReasonNoWindow = "NoWindow"
+)
+
+var (
+	IntentStorageReasonBound = "VolumesBound"
+	// IntentStorageReasonPending is reported while any PersistentVolumeClaim is waiting to be bound,
+	// such as the ones of a StorageClass binding upon the first consumer.
+	IntentStorageReasonPending = "VolumesPending"
+	// IntentStorageReasonLost is reported when the PersistentVolume of any PersistentVolumeClaim has been lost.
+	IntentStorageReasonLost = "VolumesLost"
+	// IntentStorageReasonFailed is reported when any PersistentVolumeClaim cannot be provisioned,
+	// such as for a volume source type with no StorageClass configured.
+	IntentStorageReasonFailed = "VolumesProvisioningFailed"
+)
+
+var (
+	// IntentNegotiationReasonAccepted is reported when a PeeringCandidate has been accepted in a negotiation round.
+	IntentNegotiationReasonAccepted = "NegotiationAccepted"
+	// IntentNegotiationReasonQueued is reported when no offer has been accepted, and a new discovery is queued.
+	IntentNegotiationReasonQueued = "NegotiationQueued"
+	// IntentNegotiationReasonFailed is reported when no offer has been accepted, and the fail fallback is applied.
+	IntentNegotiationReasonFailed = "NegotiationFailed"
+	// IntentNegotiationReasonApprovalRequired is reported when no offer has been accepted,
+	// and the best one is notified waiting for the Intent constraints to be updated.
+	IntentNegotiationReasonApprovalRequired = "NegotiationApprovalRequired"
+)
+
+// IntentSolverReasonQueued is reported in the Solver condition while the Intent is waiting for provider capacity.
+var IntentSolverReasonQueued = "Queued"
+
+// IntentCleanupFinalizer guards the teardown of the FLUIDOS and Liqo resources allocated for the Intent.
+const IntentCleanupFinalizer = "flare.clastix.io/cleanup"
+
+// IntentSubmissionAnnotation stores the submitted Intent payload, used as base document for partial updates.
+const IntentSubmissionAnnotation = "flare.clastix.io/submission"
+
+// TenantPriorityAnnotation sets the priority of the Capsule Tenant Intents waiting for capacity, the higher the sooner.
+const TenantPriorityAnnotation = "flare.clastix.io/priority"
+
+// VolumeSourceURIAnnotation exposes the volume source URI on its PersistentVolumeClaim,
+// to be referenced by the StorageClass parameters of the source CSI driver.
+const VolumeSourceURIAnnotation = "flare.clastix.io/source-uri"
+
+// VolumeSourceCredentialsAnnotation exposes the name of the Secret storing the volume source credentials, in the claim
+// Namespace, on its PersistentVolumeClaim, to be referenced by the StorageClass parameters of the source CSI driver.
+const VolumeSourceCredentialsAnnotation = "flare.clastix.io/source-credentials"
+
+// TenantSecretLabel marks the Tenant secrets managed through the API, valued with the Tenant name.
+const TenantSecretLabel = "tenant-secret"
+
+// TokenPermissionsAnnotation lists the permissions granted to the Tenant token, comma separated:
+// tokens with no such annotation are not restricted.
+const TokenPermissionsAnnotation = "flare.clastix.io/permissions"
+
+// TenantUserExtraKey is the extra of the users authenticated by the OIDC provider naming their Capsule Tenant.
+const TenantUserExtraKey = "flare.clastix.io/tenant"
+
+// TokenExpirationAnnotation stores the expiration time of the Tenant token bound to the Secret, in RFC 3339 format.
+const TokenExpirationAnnotation = "flare.clastix.io/expires-at"
+
+// TenantSecretName returns the name of the Secret storing the Tenant secret, in the Namespace of the Tenant tokens:
+// the dot separator prevents any clash among the Tenants, and with the tokens.
+func TenantSecretName(tenant, name string) string {
+	return tenant + "." + name
+}
+
+// InlineSecretName returns the name of the Secret storing the values supplied inline with the Intent submission,
+// in the Intent Namespace.
+func InlineSecretName(intent *Intent) string {
+	return intent.Name + "-inline"
+}
+
+// IntentAllocation references a Solver generation, and the provider cluster it has been offloaded to.
+type IntentAllocation struct {
+	// Generation of the Solver, the first one is named after the Intent Namespace.
+	Generation int64 `json:"generation"`
+	// Hash of the resources and constraints the Solver has been created upon.
+	Hash string `json:"hash,omitempty"`
+	// ClusterID is the Liqo cluster ID of the provider selected by the Solver.
+	ClusterID string `json:"clusterID,omitempty"`
+	// Candidate is the PeeringCandidate selected upon the Intent objective among the discovered ones.
+	Candidate *IntentCandidate `json:"candidate,omitempty"`
+	// Negotiation is the last negotiation performed upon the PeeringCandidates discovered by the Solver.
+	Negotiation *IntentNegotiation `json:"negotiation,omitempty"`
+}
+
+// IntentCandidate reports the score of the selected PeeringCandidate.
+type IntentCandidate struct {
+	// Name of the PeeringCandidate.
+	Name string `json:"name"`
+	// Objective the PeeringCandidates have been ranked upon.
+	Objective IntentObject `json:"objective,omitempty"`
+	// Score is the weighted sum of the normalized metrics, the higher the better.
+	Score float64 `json:"score"`
+	// Breakdown contains the metrics the score is made of, normalized between 0 and 1 among the discovered candidates.
+	Breakdown map[string]float64 `json:"breakdown,omitempty"`
+	// Round is the negotiation round the PeeringCandidate has been accepted in, the first one requires an exact match.
+	Round int `json:"round,omitempty"`
+	// Deviation is the largest relative gap between the accepted offer and the Intent price and resources requirements.
+	Deviation float64 `json:"deviation,omitempty"`
+}
+
+// IntentNegotiation reports the outcome of the negotiation rounds.
+type IntentNegotiation struct {
+	// Rounds performed by the negotiation.
+	Rounds int `json:"rounds"`
+	// Outcome is the Solver condition reason the negotiation ended with.
+	Outcome string `json:"outcome"`
+	// ObservedGeneration is the Intent generation the negotiation has been performed upon.
+	ObservedGeneration int64 `json:"observedGeneration"`
+	// Time the negotiation has ended at.
+	Time metav1.Time `json:"time"`
+}
+
+// IntentCost meters the Intent cost upon the hourly rate agreed in the FLUIDOS Contract, expressed in EUR.
+type IntentCost struct {
+	// HourlyRate agreed in the Contract of the running allocation.
+	HourlyRate float64 `json:"hourlyRate,omitempty"`
+	// Accrued is the cost accumulated up to the last metering.
+	Accrued float64 `json:"accrued,omitempty"`
+	// LastMeteringTime is the time the accrued cost has been last updated.
+	LastMeteringTime *metav1.Time `json:"lastMeteringTime,omitempty"`
+}
+
+// IntentEmissions estimates the carbon emissions of the running allocations, expressed in CO2 equivalent.
+type IntentEmissions struct {
+	// Rate is the emission rate estimated upon the Flavor of the running allocation, in grams per hour.
+	Rate float64 `json:"rate,omitempty"`
+	// Accrued is the amount emitted up to the last metering, in kilograms.
+	Accrued float64 `json:"accrued,omitempty"`
+}
+
+// IntentEndpoint is the URL an exposed port of the workload is reachable at.
+type IntentEndpoint struct {
+	Port int32 `json:"port"`
+	// URL is the HTTPS one when the TLS certificate is issued, HTTP otherwise.
+	URL string `json:"url"`
+}
+
+// IntentQueue tracks an Intent waiting for provider capacity, retrying the discovery with backoff.
+type IntentQueue struct {
+	// Since is the time the Intent has been queued at.
+	Since metav1.Time `json:"since"`
+	// Position of the Intent in the queue, starting from 1.
+	Position int `json:"position,omitempty"`
+	// Attempts is the number of discoveries retried since the Intent has been queued.
+	Attempts int32 `json:"attempts,omitempty"`
+	// LastAttemptTime is the time the discovery has been last retried at.
+	LastAttemptTime *metav1.Time `json:"lastAttemptTime,omitempty"`
+	// NextAttemptTime is the time the discovery is retried at, unset while the retried discovery is in progress.
+	NextAttemptTime *metav1.Time `json:"nextAttemptTime,omitempty"`
+}
+
+// IntentWindow is a time range the workload is allowed to run in.
+type IntentWindow struct {
+	Start metav1.Time `json:"start"`
+	End   metav1.Time `json:"end"`
+}
+
 type IntentStatus struct {
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Allocation is the Solver generation currently running the workload.
+	Allocation IntentAllocation `json:"allocation,omitempty"`
+	// Migration is the Solver generation being allocated upon a resources or constraints change.
+	Migration *IntentAllocation `json:"migration,omitempty"`
+	// Retiring contains the Solver generations replaced by a migration, waiting for their release.
+	Retiring []IntentAllocation `json:"retiring,omitempty"`
+	// Cost metered for the running allocations.
+	Cost IntentCost `json:"cost,omitempty"`
+	// Emissions estimated for the running allocations, metered along with the cost.
+	Emissions IntentEmissions `json:"emissions,omitempty"`
+	// NextWindow is the availability window the workload is running in, or the next one it's scheduled for.
+	NextWindow *IntentWindow `json:"nextWindow,omitempty"`
+	// Queue tracks the Intent waiting for provider capacity.
+	Queue *IntentQueue `json:"queue,omitempty"`
+	// Endpoints are the URLs of the exposed ports, along with the domains assigned to the ones with no custom domain.
+	Endpoints []IntentEndpoint `json:"endpoints,omitempty"`
+	// AdmittedDeadline is the deadline the batch workload has been admitted for:
+	// the admission is not evaluated again when the Job is recreated, unless the deadline changes.
+	AdmittedDeadline *metav1.Time `json:"admittedDeadline,omitempty"`
 }
 
 type IntentObject string
@@ -53,8 +274,12 @@ type IntentWorkloadPort struct {
 	//+kubebuilder:default=TCP
 	//+kubebuilder:validation:Enum=TCP;UDP
 	Protocol string `json:"protocol,omitempty"`
-	Expose   bool   `json:"expose,omitempty"`
-	Domain   string `json:"domain,omitempty"`
+	// AppProtocol of the TCP port: HTTP ports are exposed at their domain, the other ones at the exposure address.
+	//+kubebuilder:default=HTTP
+	//+kubebuilder:validation:Enum=HTTP;TCP
+	AppProtocol string `json:"appProtocol,omitempty"`
+	Expose      bool   `json:"expose,omitempty"`
+	Domain      string `json:"domain,omitempty"`
 }
 
 type IntentWorkloadResourceGPU struct {
@@ -91,10 +316,9 @@ type IntentWorkloadResource struct {
 }
 
 type IntentConstraintAvailabilityMaintenanceWindow struct {
-	//+kubebuilder:validation:Pattern=`^\d{4}-(0[1-9]|1[0-2])-(0[1-9]|[12]\d|3[01])$`
-	Start string `json:"start"`
-	//+kubebuilder:validation:Pattern=`^\d{4}-(0[1-9]|1[0-2])-(0[1-9]|[12]\d|3[01])$`
-	End string `json:"end"`
+	// Start of the first maintenance window, repeated upon the given frequency.
+	Start metav1.Time `json:"start"`
+	End   metav1.Time `json:"end"`
 	//+kubebuilder:default=Weekly
 	//+kubebuilder:validation:Enum=Weekly;Monthly
 	Frequency string `json:"frequency"`
@@ -125,14 +349,17 @@ type IntentWorkloadConstraintNegotiation struct {
 	//+kubebuilder:default=300
 	TimeoutSeconds int64 `json:"timeoutSeconds,omitempty"`
 	//+kubebuilder:default=queue
+	//+kubebuilder:validation:Enum=queue;fail;notify
 	FallbackStrategy string `json:"fallbackStrategy,omitempty"`
 	//+kubebuilder:default=0.05
 	AutoAcceptThreshold float64 `json:"autoAcceptThreshold,omitempty"`
 }
 
 type IntentWorkloadConstraintEnergy struct {
-	MaxCarbonFootprint     string `json:"maxCarbonFootprint,omitempty"`
-	RenewableEnergyOnly    bool   `json:"renewableEnergyOnly,omitempty"`
+	//+kubebuilder:validation:Pattern=`(?i)^\s*\d+(\.\d+)?\s*(g|kg)\s*CO2e?\s*/\s*h\s*$`
+	MaxCarbonFootprint  string `json:"maxCarbonFootprint,omitempty"`
+	RenewableEnergyOnly bool   `json:"renewableEnergyOnly,omitempty"`
+	//+kubebuilder:validation:Enum=A;B;C;D;E;F
 	EnergyEfficiencyRating string `json:"energyEfficiencyRating,omitempty"`
 	//+kubebuilder:default=2.0
 	PowerUsageEffectiveness float32 `json:"powerUsageEffectiveness,omitempty"`
@@ -185,21 +412,23 @@ type IntentWorkloadConstraintSecurity struct {
 }
 
 type IntentConstraint struct {
-	MaxHourlyCost    float64 `json:"maxHourlyCost,omitempty"`
-	MaxTotalCost     float64 `json:"maxTotalCost,omitempty"` //TODO(prometherion): advanced
-	Location         string  `json:"location,omitempty"`     //TODO(prometherion): advanced
+	MaxHourlyCost float64 `json:"maxHourlyCost,omitempty"`
+	// MaxTotalCost is the budget of the Intent: once the accrued cost exceeds it, the workload is suspended,
+	// and the cost of the still held allocation is not metered anymore, until the budget is raised.
+	MaxTotalCost     float64 `json:"maxTotalCost,omitempty"`
+	Location         string  `json:"location,omitempty"`
 	AvailabilityZone string  `json:"availabilityZone,omitempty"`
 	//+kubebuilder:default=100
 	MaxLatencyMs int64                                `json:"maxLatencyMs,omitempty"` //TODO(prometherion): advanced
-	Deadline     metav1.Time                          `json:"deadline,omitempty"`     //TODO(prometherion): advanced
+	Deadline     metav1.Time                          `json:"deadline,omitempty"`
 	PreEmptible  bool                                 `json:"preEmptible,omitempty"`
 	Providers    []string                             `json:"providers,omitempty"`
-	Availability IntentWorkloadConstraintAvailability `json:"availability,omitempty"` //TODO(prometherion): advanced
-	Negotiation  IntentWorkloadConstraintNegotiation  `json:"negotiation,omitempty"`  //TODO(prometherion): advanced
-	Energy       IntentWorkloadConstraintEnergy       `json:"energy,omitempty"`       //TODO(prometherion): advanced
-	Compliance   IntentWorkloadConstraintCompliance   `json:"compliance,omitempty"`   //TODO(prometherion): advanced
-	Performance  IntentWorkloadConstraintPerformance  `json:"performance,omitempty"`  //TODO(prometherion): advanced
-	Security     IntentWorkloadConstraintSecurity     `json:"security,omitempty"`     //TODO(prometherion): advanced
+	Availability IntentWorkloadConstraintAvailability `json:"availability,omitempty"`
+	Negotiation  IntentWorkloadConstraintNegotiation  `json:"negotiation,omitempty"`
+	Energy       IntentWorkloadConstraintEnergy       `json:"energy,omitempty"`
+	Compliance   IntentWorkloadConstraintCompliance   `json:"compliance,omitempty"`
+	Performance  IntentWorkloadConstraintPerformance  `json:"performance,omitempty"` //TODO(prometherion): advanced
+	Security     IntentWorkloadConstraintSecurity     `json:"security,omitempty"`
 }
 
 type IntentWorkloadBatch struct {
@@ -235,9 +464,17 @@ type IntentWorkloadSecret struct {
 	Name string `json:"name"`
 	// Env is the environment variable the Secret will be injected in the workload.
 	Env string `json:"env"`
+	// From is the name of the Tenant secret the value is copied from:
+	// when empty, the value has been supplied inline at the submission.
+	From string `json:"from,omitempty"`
+	// Key is the key of the Tenant secret, defaults to the Secret name.
+	Key string `json:"key,omitempty"`
 }
 
 type IntentWorkloadStorageVolumeSource struct {
+	// Credentials is the name of the Tenant secret storing the source credentials:
+	// it's copied in the Intent Namespace, for the PersistentVolumeClaim to reference the copy.
+	//+kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
 	Credentials string `json:"credentials,omitempty"`
 	//+kubebuilder:validation:Enum=S3;GCS;Azure
 	Type string `json:"type,omitempty"`
@@ -261,9 +498,9 @@ type IntentWorkload struct {
 	//+kubebuilder:validation:Enum=Service;Batch
 	Type IntentWorkloadType `json:"type"`
 	//+kubebuilder:validation:Enum=AllReduce;Independent;Pipeline
-	CommunicationPattern string `json:"communicationPattern,omitempty"` //TODO(prometherion): advanced
+	CommunicationPattern string `json:"communicationPattern,omitempty"`
 	//+kubebuilder:validation:Enum=Colocated;Distributed;Flexible
-	DeploymentStrategy string              `json:"deploymentStrategy,omitempty"` //TODO(prometherion): advanced
+	DeploymentStrategy string              `json:"deploymentStrategy,omitempty"`
 	Batch              IntentWorkloadBatch `json:"batch,omitempty"`
 	Name               string              `json:"name"`
 	Image              string              `json:"image"`
@@ -273,7 +510,7 @@ type IntentWorkload struct {
 	Secrets   []IntentWorkloadSecret `json:"secrets,omitempty"`
 	Storage   IntentWorkloadStorage  `json:"storage,omitempty"`
 	Ports     []IntentWorkloadPort   `json:"ports,omitempty"`
-	Scaling   IntentWorkloadScaling  `json:"scaling,omitempty"` //TODO(prometherion): advanced
+	Scaling   IntentWorkloadScaling  `json:"scaling,omitempty"`
 	Resources IntentWorkloadResource `json:"resources,omitempty"`
 }
 
@@ -285,7 +522,7 @@ type IntentSLA struct {
 
 type IntentSpec struct {
 	Constraints IntentConstraint `json:"contraints,omitempty"`
-	Objective   IntentObject     `json:"objective"`     //TODO(prometherion): advanced
+	Objective   IntentObject     `json:"objective"`
 	SLA         IntentSLA        `json:"sla,omitempty"` //TODO(prometherion): advanced
 	Workload    IntentWorkload   `json:"workload"`
 }
@@ -39,6 +39,53 @@ func (in *Intent) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntentAllocation) DeepCopyInto(out *IntentAllocation) {
+	*out = *in
+	if in.Candidate != nil {
+		in, out := &in.Candidate, &out.Candidate
+		*out = new(IntentCandidate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Negotiation != nil {
+		in, out := &in.Negotiation, &out.Negotiation
+		*out = new(IntentNegotiation)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntentAllocation.
+func (in *IntentAllocation) DeepCopy() *IntentAllocation {
+	if in == nil {
+		return nil
+	}
+	out := new(IntentAllocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntentCandidate) DeepCopyInto(out *IntentCandidate) {
+	*out = *in
+	if in.Breakdown != nil {
+		in, out := &in.Breakdown, &out.Breakdown
+		*out = make(map[string]float64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntentCandidate.
+func (in *IntentCandidate) DeepCopy() *IntentCandidate {
+	if in == nil {
+		return nil
+	}
+	out := new(IntentCandidate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IntentConstraint) DeepCopyInto(out *IntentConstraint) {
 	*out = *in
@@ -69,6 +116,8 @@ func (in *IntentConstraint) DeepCopy() *IntentConstraint {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IntentConstraintAvailabilityMaintenanceWindow) DeepCopyInto(out *IntentConstraintAvailabilityMaintenanceWindow) {
 	*out = *in
+	in.Start.DeepCopyInto(&out.Start)
+	in.End.DeepCopyInto(&out.End)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntentConstraintAvailabilityMaintenanceWindow.
@@ -81,6 +130,55 @@ func (in *IntentConstraintAvailabilityMaintenanceWindow) DeepCopy() *IntentConst
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntentCost) DeepCopyInto(out *IntentCost) {
+	*out = *in
+	if in.LastMeteringTime != nil {
+		in, out := &in.LastMeteringTime, &out.LastMeteringTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntentCost.
+func (in *IntentCost) DeepCopy() *IntentCost {
+	if in == nil {
+		return nil
+	}
+	out := new(IntentCost)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntentEmissions) DeepCopyInto(out *IntentEmissions) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntentEmissions.
+func (in *IntentEmissions) DeepCopy() *IntentEmissions {
+	if in == nil {
+		return nil
+	}
+	out := new(IntentEmissions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntentEndpoint) DeepCopyInto(out *IntentEndpoint) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntentEndpoint.
+func (in *IntentEndpoint) DeepCopy() *IntentEndpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(IntentEndpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IntentList) DeepCopyInto(out *IntentList) {
 	*out = *in
@@ -113,6 +211,46 @@ func (in *IntentList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntentNegotiation) DeepCopyInto(out *IntentNegotiation) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntentNegotiation.
+func (in *IntentNegotiation) DeepCopy() *IntentNegotiation {
+	if in == nil {
+		return nil
+	}
+	out := new(IntentNegotiation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntentQueue) DeepCopyInto(out *IntentQueue) {
+	*out = *in
+	in.Since.DeepCopyInto(&out.Since)
+	if in.LastAttemptTime != nil {
+		in, out := &in.LastAttemptTime, &out.LastAttemptTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextAttemptTime != nil {
+		in, out := &in.NextAttemptTime, &out.NextAttemptTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntentQueue.
+func (in *IntentQueue) DeepCopy() *IntentQueue {
+	if in == nil {
+		return nil
+	}
+	out := new(IntentQueue)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IntentSLA) DeepCopyInto(out *IntentSLA) {
 	*out = *in
@@ -161,6 +299,40 @@ func (in *IntentStatus) DeepCopyInto(out *IntentStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	in.Allocation.DeepCopyInto(&out.Allocation)
+	if in.Migration != nil {
+		in, out := &in.Migration, &out.Migration
+		*out = new(IntentAllocation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Retiring != nil {
+		in, out := &in.Retiring, &out.Retiring
+		*out = make([]IntentAllocation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Cost.DeepCopyInto(&out.Cost)
+	out.Emissions = in.Emissions
+	if in.NextWindow != nil {
+		in, out := &in.NextWindow, &out.NextWindow
+		*out = new(IntentWindow)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Queue != nil {
+		in, out := &in.Queue, &out.Queue
+		*out = new(IntentQueue)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]IntentEndpoint, len(*in))
+		copy(*out, *in)
+	}
+	if in.AdmittedDeadline != nil {
+		in, out := &in.AdmittedDeadline, &out.AdmittedDeadline
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntentStatus.
@@ -173,6 +345,23 @@ func (in *IntentStatus) DeepCopy() *IntentStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntentWindow) DeepCopyInto(out *IntentWindow) {
+	*out = *in
+	in.Start.DeepCopyInto(&out.Start)
+	in.End.DeepCopyInto(&out.End)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntentWindow.
+func (in *IntentWindow) DeepCopy() *IntentWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(IntentWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IntentWorkload) DeepCopyInto(out *IntentWorkload) {
 	*out = *in
@@ -244,7 +433,9 @@ func (in *IntentWorkloadConstraintAvailability) DeepCopyInto(out *IntentWorkload
 	if in.MaintenanceWindows != nil {
 		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
 		*out = make([]IntentConstraintAvailabilityMaintenanceWindow, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
@@ -6,6 +6,9 @@ package main
 import (
 	"flag"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/pflag"
 	"go.uber.org/zap/zapcore"
@@ -21,7 +24,27 @@ func main() {
 	setupLog := ctrl.Log.WithName("setup")
 
 	var enableLeaderElection bool
+	var gpuUtilizationMetric, gpuResourceName string
+	var budgetWarningThresholds string
+	var queueBackoff, queueMaxBackoff time.Duration
+	var queueConcurrency int
+	var storageClass, sourceStorageClasses string
+	var ingressClass, ingressClusterIssuer, ingressDomain string
+	var exposureBackend, gateway string
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false, "Enable leader election for controller manager. Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&gpuUtilizationMetric, "gpu-utilization-metric", "DCGM_FI_DEV_GPU_UTIL", "Name of the Pods custom metric used by the HorizontalPodAutoscaler to scale upon the GPU utilization, leave empty to disable GPU-based autoscaling.")
+	flag.StringVar(&gpuResourceName, "gpu-resource-name", "nvidia.com/gpu", "Extended resource the workload containers request the GPUs through, leave empty to not request them.")
+	flag.StringVar(&budgetWarningThresholds, "budget-warning-thresholds", "80", "Comma-separated percentages of the Intent maximum total cost reporting a budget warning once crossed by the accrued cost.")
+	flag.DurationVar(&queueBackoff, "queue-backoff", 30*time.Second, "Delay of the first discovery retried for an Intent queued waiting for capacity, doubling at each attempt.")
+	flag.DurationVar(&queueMaxBackoff, "queue-max-backoff", 10*time.Minute, "Maximum delay between the discoveries retried for an Intent queued waiting for capacity.")
+	flag.IntVar(&queueConcurrency, "queue-concurrency", 1, "Number of queued Intents allowed to retry their discovery at the same time.")
+	flag.StringVar(&storageClass, "storage-class", "liqo", "StorageClass of the persistent volumes with no source, the Liqo virtual one provisions them in the provider cluster: leave empty to use the cluster default.")
+	flag.StringVar(&sourceStorageClasses, "source-storage-classes", "", "Comma-separated StorageClasses of the persistent volumes backed by an object storage source, in the form S3=<class>,GCS=<class>,Azure=<class>.")
+	flag.StringVar(&ingressClass, "ingress-class", "", "IngressClass of the Ingress exposing the workload ports, leave empty to use the cluster default.")
+	flag.StringVar(&ingressClusterIssuer, "ingress-cluster-issuer", "", "cert-manager ClusterIssuer issuing the TLS certificates of the exposed ports, leave empty to expose them over plain HTTP.")
+	flag.StringVar(&ingressDomain, "ingress-domain", "", "Wildcard domain the exposed ports with no custom domain are assigned a subdomain of, e.g. apps.example.com.")
+	flag.StringVar(&exposureBackend, "exposure-backend", controllers.ExposureBackendIngress, "Backend exposing the workload ports, one of ingress (HTTP ports through an Ingress, the other ones through a LoadBalancer Service), loadbalancer, or gateway.")
+	flag.StringVar(&gateway, "gateway", "", "Gateway the routes of the gateway exposure backend are attached to, in the form <namespace>/<name>.")
 	opts := zap.Options{
 		Development: true,
 		EncoderConfigOptions: append([]zap.EncoderConfigOption{}, func(config *zapcore.EncoderConfig) {
@@ -38,6 +61,60 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	var warningThresholds []int
+
+	for _, threshold := range strings.Split(budgetWarningThresholds, ",") {
+		if threshold = strings.TrimSpace(threshold); threshold == "" {
+			continue
+		}
+
+		percentage, err := strconv.Atoi(threshold)
+		if err != nil || percentage <= 0 || percentage >= 100 {
+			setupLog.Error(err, "invalid budget warning threshold, must be a percentage between 1 and 99", "threshold", threshold)
+			os.Exit(1)
+		}
+
+		warningThresholds = append(warningThresholds, percentage)
+	}
+
+	if queueBackoff <= 0 || queueMaxBackoff < queueBackoff {
+		setupLog.Error(nil, "invalid queue backoff, must be positive and not greater than the maximum one", "backoff", queueBackoff, "max", queueMaxBackoff)
+		os.Exit(1)
+	}
+	// With no concurrent discovery allowed, the queued Intents would never be retried.
+	if queueConcurrency < 1 {
+		setupLog.Error(nil, "invalid queue concurrency, must be at least 1", "concurrency", queueConcurrency)
+		os.Exit(1)
+	}
+
+	sourceClasses := map[string]string{}
+
+	for _, mapping := range strings.Split(sourceStorageClasses, ",") {
+		if mapping = strings.TrimSpace(mapping); mapping == "" {
+			continue
+		}
+
+		source, class, found := strings.Cut(mapping, "=")
+		if !found || class == "" || (source != "S3" && source != "GCS" && source != "Azure") {
+			setupLog.Error(nil, "invalid source StorageClass, must be in the form <S3|GCS|Azure>=<class>", "mapping", mapping)
+			os.Exit(1)
+		}
+
+		sourceClasses[source] = class
+	}
+
+	switch exposureBackend {
+	case controllers.ExposureBackendIngress, controllers.ExposureBackendLoadBalancer:
+	case controllers.ExposureBackendGateway:
+		if namespace, name, found := strings.Cut(gateway, "/"); !found || namespace == "" || name == "" {
+			setupLog.Error(nil, "invalid Gateway, must be in the form <namespace>/<name>", "gateway", gateway)
+			os.Exit(1)
+		}
+	default:
+		setupLog.Error(nil, "invalid exposure backend, must be one of ingress, loadbalancer, or gateway", "backend", exposureBackend)
+		os.Exit(1)
+	}
+
 	scheme, schemeErr := scheme.New()
 	if schemeErr != nil {
 		setupLog.Error(schemeErr, "failed to initialize scheme")
@@ -59,7 +136,7 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err := (&controllers.IntentReconciler{Client: mgr.GetClient()}).SetupWithManager(mgr); err != nil {
+	if err := (&controllers.IntentReconciler{Client: mgr.GetClient(), Recorder: mgr.GetEventRecorderFor("flare"), GPUUtilizationMetric: gpuUtilizationMetric, GPUResourceName: gpuResourceName, BudgetWarningThresholds: warningThresholds, QueueBackoff: queueBackoff, QueueMaxBackoff: queueMaxBackoff, QueueConcurrency: queueConcurrency, StorageClass: storageClass, SourceStorageClasses: sourceClasses, IngressClass: ingressClass, IngressClusterIssuer: ingressClusterIssuer, IngressDomain: ingressDomain, ExposureBackend: exposureBackend, Gateway: gateway}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(mgrErr, "unable to setup controllers.IntentReconciler")
 		os.Exit(1)
 	}
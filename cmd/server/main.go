@@ -5,6 +5,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"net/http"
 	"time"
 
@@ -15,6 +16,7 @@ import (
 	"github.com/pkg/errors"
 	capsuleindexer "github.com/projectcapsule/capsule/pkg/indexer"
 	"github.com/projectcapsule/capsule/pkg/indexer/tenant"
+	"k8s.io/client-go/kubernetes"
 	ctrl "sigs.k8s.io/controller-runtime"
 	ctrllogger "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
@@ -27,6 +29,27 @@ import (
 )
 
 func main() {
+	var oidcIssuerURL, oidcClientID string
+	var oidcUsernameClaim, oidcUsernamePrefix, oidcGroupsClaim, oidcGroupsPrefix, oidcTenantClaim string
+	var oidcRefreshInterval time.Duration
+	flag.StringVar(&oidcIssuerURL, "oidc-issuer-url", "", "URL of the OIDC provider whose ID tokens are authenticated alongside the ServiceAccount ones, leave empty to disable OIDC authentication.")
+	flag.StringVar(&oidcClientID, "oidc-client-id", "", "Client ID the OIDC ID tokens must be issued for, required when the OIDC issuer is set.")
+	flag.StringVar(&oidcUsernameClaim, "oidc-username-claim", "sub", "Claim of the OIDC ID tokens the username is taken from.")
+	flag.StringVar(&oidcUsernamePrefix, "oidc-username-prefix", "", "Prefix prepended to the OIDC usernames, preventing clashes with the other Tenant owners, e.g. oidc:.")
+	flag.StringVar(&oidcGroupsClaim, "oidc-groups-claim", "groups", "Claim of the OIDC ID tokens the groups are taken from.")
+	flag.StringVar(&oidcGroupsPrefix, "oidc-groups-prefix", "", "Prefix prepended to the OIDC groups, preventing clashes with the other Tenant owners, e.g. oidc:.")
+	flag.StringVar(&oidcTenantClaim, "oidc-tenant-claim", "", "Claim of the OIDC ID tokens naming the Tenant of the user, leave empty to map the users to the Tenants they own as User or through their Groups.")
+	flag.DurationVar(&oidcRefreshInterval, "oidc-jwks-refresh-interval", time.Hour, "Interval the signing keys of the OIDC provider are refreshed at, besides when an ID token is signed with an unknown key.")
+	var tokenReviewCacheSize int
+	var tokenReviewCacheTTL time.Duration
+	flag.IntVar(&tokenReviewCacheSize, "token-review-cache-size", 1024, "Maximum number of the reviewed ServiceAccount tokens cached, evicting the least recently used ones.")
+	flag.DurationVar(&tokenReviewCacheTTL, "token-review-cache-ttl", time.Minute, "Time the reviewed ServiceAccount tokens are cached for, capped by their expiration: set to 0 to review each request token.")
+	var metricsBindAddress string
+	flag.StringVar(&metricsBindAddress, "metrics-bind-address", "0", "Address the metrics endpoint binds to, such as the token review cache ones, e.g. :8081: set to 0 to disable it.")
+	var ingressDomain string
+	flag.StringVar(&ingressDomain, "ingress-domain", "", "Wildcard domain the operator assigns the exposed ports with no custom domain a subdomain of, reserved from the custom domains: it must match the operator one.")
+	flag.Parse()
+
 	e := echo.New()
 
 	e.HideBanner = true
@@ -45,7 +68,7 @@ func main() {
 	mgr, mgrErr := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme: k8sScheme,
 		Metrics: server.Options{
-			BindAddress: "0",
+			BindAddress: metricsBindAddress,
 		},
 		LeaderElection: false,
 	})
@@ -53,11 +76,16 @@ func main() {
 		e.Logger.Fatalf("cannot initialize manager, %s", mgrErr.Error())
 	}
 
+	clientset, clientsetErr := kubernetes.NewForConfig(mgr.GetConfig())
+	if clientsetErr != nil {
+		e.Logger.Fatalf("cannot initialize Kubernetes clientset, %s", clientsetErr.Error())
+	}
+
 	ctx := ctrl.SetupSignalHandler()
 
-	tenantOwnerRefIndexer, intentUIDIndexer := tenant.OwnerReference{}, indexer.IntentUID{}
+	tenantOwnerRefIndexer, intentUIDIndexer, intentDomainIndexer := tenant.OwnerReference{}, indexer.IntentUID{}, indexer.IntentDomain{}
 
-	for _, index := range []capsuleindexer.CustomIndexer{tenantOwnerRefIndexer, intentUIDIndexer} {
+	for _, index := range []capsuleindexer.CustomIndexer{tenantOwnerRefIndexer, intentUIDIndexer, intentDomainIndexer} {
 		if err := mgr.GetFieldIndexer().IndexField(ctx, index.Object(), index.Field(), index.Func()); err != nil {
 			e.Logger.Fatalf("cannot initialize indexer, %s", err.Error())
 		}
@@ -65,7 +93,8 @@ func main() {
 
 	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
-			if c.Request().Header.Get(echo.HeaderContentType) != echo.MIMEApplicationJSON {
+			// Neither the GET requests, nor the upgraded pod streams, carry a JSON body.
+			if c.Request().Method != http.MethodGet && c.Request().Header.Get(echo.HeaderUpgrade) == "" && c.Request().Header.Get(echo.HeaderContentType) != echo.MIMEApplicationJSON {
 				return c.JSON(http.StatusUnsupportedMediaType, map[string]string{
 					"error": "Content-Type must be application/json",
 				})
@@ -74,7 +103,33 @@ func main() {
 		}
 	})
 	e.Use(middleware.Logger())
-	e.Use(middlewares.JWTAuthenticationMiddleware(mgr.GetClient()))
+	var authenticators []middlewares.Authenticator
+	if oidcIssuerURL != "" {
+		if oidcClientID == "" {
+			e.Logger.Fatalf("the OIDC client ID is required along with the OIDC issuer")
+		}
+
+		authenticators = append(authenticators, &middlewares.OIDCAuthenticator{
+			IssuerURL:       oidcIssuerURL,
+			ClientID:        oidcClientID,
+			UsernameClaim:   oidcUsernameClaim,
+			UsernamePrefix:  oidcUsernamePrefix,
+			GroupsClaim:     oidcGroupsClaim,
+			GroupsPrefix:    oidcGroupsPrefix,
+			TenantClaim:     oidcTenantClaim,
+			RefreshInterval: oidcRefreshInterval,
+		})
+	}
+
+	var tokenReviewCache *middlewares.TokenReviewCache
+	if tokenReviewCacheTTL > 0 && tokenReviewCacheSize > 0 {
+		tokenReviewCache = middlewares.NewTokenReviewCache(tokenReviewCacheSize, tokenReviewCacheTTL)
+	}
+
+	authenticators = append(authenticators, &middlewares.ServiceAccountAuthenticator{Client: mgr.GetClient(), Cache: tokenReviewCache})
+
+	e.Use(middlewares.JWTAuthenticationMiddleware(authenticators...))
+	e.Use(middlewares.PermissionsMiddleware(mgr.GetClient()))
 
 	helper := handlers.Helper{
 		Client:                mgr.GetClient(),
@@ -83,14 +138,24 @@ func main() {
 
 	api.RegisterHandlers(e, &handlers.Server{
 		Intent: handlers.Intent{
-			Helper:           helper,
-			Client:           mgr.GetClient(),
-			IntentUIDIndexer: intentUIDIndexer,
+			Helper:              helper,
+			Client:              mgr.GetClient(),
+			Clientset:           clientset,
+			Informers:           mgr.GetCache(),
+			IntentUIDIndexer:    intentUIDIndexer,
+			IntentDomainIndexer: intentDomainIndexer,
+			RESTConfig:          mgr.GetConfig(),
+			IngressDomain:       ingressDomain,
 		},
-		Token: handlers.Token{
+		Secret: handlers.Secret{
 			Helper: helper,
 			Client: mgr.GetClient(),
 		},
+		Token: handlers.Token{
+			Helper:           helper,
+			Client:           mgr.GetClient(),
+			TokenReviewCache: tokenReviewCache,
+		},
 	})
 
 	srv := &http.Server{
@@ -126,6 +126,12 @@ const (
 	Weekly  MaintenanceWindowFrequency = "weekly"
 )
 
+// Defines values for PortAppProtocol.
+const (
+	Http PortAppProtocol = "http"
+	Tcp  PortAppProtocol = "tcp"
+)
+
 // Defines values for PortProtocol.
 const (
 	TCP PortProtocol = "TCP"
@@ -286,6 +292,12 @@ type Constraints struct {
 // ConstraintsProviders defines model for Constraints.Providers.
 type ConstraintsProviders string
 
+// CreateSecretRequest defines model for CreateSecretRequest.
+type CreateSecretRequest struct {
+	Data map[string]string `json:"data"`
+	Name string            `json:"name"`
+}
+
 // CreateTokenRequest defines model for CreateTokenRequest.
 type CreateTokenRequest struct {
 	ExpiresIn   *string   `json:"expires_in,omitempty"`
@@ -304,6 +316,21 @@ type CreateTokenResponse struct {
 	TokenId     *string    `json:"token_id,omitempty"`
 }
 
+// DeleteSecretResponse defines model for DeleteSecretResponse.
+type DeleteSecretResponse struct {
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	Message   *string    `json:"message,omitempty"`
+	Name      *string    `json:"name,omitempty"`
+}
+
+// Endpoint defines model for Endpoint.
+type Endpoint struct {
+	Port *int `json:"port,omitempty"`
+
+	// Url URL of the exposed port (e.g., "https://api.example.com", "udp://203.0.113.10:5000")
+	Url *string `json:"url,omitempty"`
+}
+
 // Energy defines model for Energy.
 type Energy struct {
 	// EnergyEfficiencyRating Minimum efficiency rating (A-F)
@@ -386,18 +413,41 @@ type Intent struct {
 	Workload    Workload         `json:"workload"`
 }
 
+// IntentEvent defines model for IntentEvent.
+type IntentEvent struct {
+	// Condition Condition of the intent the transition refers to (e.g., "Solver", "NamespaceOffloading", "Deploy")
+	Condition *string `json:"condition,omitempty"`
+
+	// IntentStatus Status of the intent after the transition, as reported by the intent status
+	IntentStatus *string `json:"intent_status,omitempty"`
+	Message      *string `json:"message,omitempty"`
+	Reason       *string `json:"reason,omitempty"`
+
+	// Status Status of the condition, one of "True", "False" or "Unknown"
+	Status    *string    `json:"status,omitempty"`
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+}
+
 // IntentObjective defines model for Intent.Objective.
 type IntentObjective string
 
 // IntentStatus defines model for IntentStatus.
 type IntentStatus struct {
-	CurrentCost    *string `json:"current_cost,omitempty"`
-	GpuUtilization *string `json:"gpu_utilization,omitempty"`
-	IntentId       *string `json:"intent_id,omitempty"`
-	Message        *string `json:"message,omitempty"`
-	Runtime        *string `json:"runtime,omitempty"`
-	Status         *string `json:"status,omitempty"`
-	WorkloadUrl    *string `json:"workload_url,omitempty"`
+	// CarbonFootprint Estimated carbon emissions of the intent (e.g., "0.42 kgCO2e")
+	CarbonFootprint *string `json:"carbon_footprint,omitempty"`
+	CurrentCost     *string `json:"current_cost,omitempty"`
+
+	// Endpoints Endpoints the exposed ports are reachable at
+	Endpoints      *[]Endpoint `json:"endpoints,omitempty"`
+	GpuUtilization *string     `json:"gpu_utilization,omitempty"`
+	IntentId       *string     `json:"intent_id,omitempty"`
+	Message        *string     `json:"message,omitempty"`
+
+	// QueuePosition Position of the intent in the queue waiting for capacity, starting from 1
+	QueuePosition *int    `json:"queue_position,omitempty"`
+	Runtime       *string `json:"runtime,omitempty"`
+	Status        *string `json:"status,omitempty"`
+	WorkloadUrl   *string `json:"workload_url,omitempty"`
 }
 
 // IntentSubmission defines model for IntentSubmission.
@@ -410,6 +460,11 @@ type ListIntentsResponse struct {
 	Tokens *[]IntentStatus `json:"tokens,omitempty"`
 }
 
+// ListSecretsResponse defines model for ListSecretsResponse.
+type ListSecretsResponse struct {
+	Secrets *[]TenantSecret `json:"secrets,omitempty"`
+}
+
 // ListTokensResponse defines model for ListTokensResponse.
 type ListTokensResponse struct {
 	Tokens *[]Token `json:"tokens,omitempty"`
@@ -463,12 +518,17 @@ type Performance struct {
 
 // Port defines model for Port.
 type Port struct {
-	Domain   *string       `json:"domain,omitempty"`
-	Expose   *bool         `json:"expose,omitempty"`
-	Port     int           `json:"port"`
-	Protocol *PortProtocol `json:"protocol,omitempty"`
+	// AppProtocol Application protocol of the TCP port, HTTP ports are exposed at their domain
+	AppProtocol *PortAppProtocol `json:"app_protocol,omitempty"`
+	Domain      *string          `json:"domain,omitempty"`
+	Expose      *bool            `json:"expose,omitempty"`
+	Port        int              `json:"port"`
+	Protocol    *PortProtocol    `json:"protocol,omitempty"`
 }
 
+// PortAppProtocol Application protocol of the TCP port, HTTP ports are exposed at their domain
+type PortAppProtocol string
+
 // PortProtocol defines model for Port.Protocol.
 type PortProtocol string
 
@@ -479,6 +539,12 @@ type Resources struct {
 	Memory *string `json:"memory,omitempty"`
 }
 
+// ResumeIntentRequest defines model for ResumeIntentRequest.
+type ResumeIntentRequest struct {
+	// MaxTotalCost Raised max total cost, it must exceed the accrued cost
+	MaxTotalCost *string `json:"max_total_cost,omitempty"`
+}
+
 // RevokeTokenResponse defines model for RevokeTokenResponse.
 type RevokeTokenResponse struct {
 	Message   *string    `json:"message,omitempty"`
@@ -509,8 +575,17 @@ type Scaling struct {
 
 // Secret defines model for Secret.
 type Secret struct {
-	Env  string `json:"env"`
-	Name string `json:"name"`
+	Env string `json:"env"`
+
+	// From Name of the Tenant secret the value is copied from
+	From *string `json:"from,omitempty"`
+
+	// Key Key of the Tenant secret, defaults to the secret name
+	Key  *string `json:"key,omitempty"`
+	Name string  `json:"name"`
+
+	// Value Inline secret value, never returned
+	Value *string `json:"value,omitempty"`
 }
 
 // Security defines model for Security.
@@ -547,6 +622,15 @@ type SubmitIntentResponse struct {
 	Status             *string    `json:"status,omitempty"`
 }
 
+// TenantSecret defines model for TenantSecret.
+type TenantSecret struct {
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+
+	// Keys Keys of the secret, the values are never returned
+	Keys *[]string `json:"keys,omitempty"`
+	Name *string   `json:"name,omitempty"`
+}
+
 // Token defines model for Token.
 type Token struct {
 	CreatedAt   *time.Time `json:"created_at,omitempty"`
@@ -606,12 +690,63 @@ type WorkloadDeploymentStrategy string
 // WorkloadType defines model for Workload.Type.
 type WorkloadType string
 
+// ExecIntentParams defines parameters for ExecIntent.
+type ExecIntentParams struct {
+	// Command Command to execute, an item for each argument
+	Command []string `form:"command" json:"command"`
+
+	// Pod Pod of the workload the command is executed in, defaulting to the first running one
+	Pod *string `form:"pod,omitempty" json:"pod,omitempty"`
+
+	// Container Container the command is executed in, defaulting to the workload one
+	Container *string `form:"container,omitempty" json:"container,omitempty"`
+
+	// Stdin Attach the standard input of the command
+	Stdin *bool `form:"stdin,omitempty" json:"stdin,omitempty"`
+
+	// Tty Allocate a terminal, merging the standard error with the standard output
+	Tty *bool `form:"tty,omitempty" json:"tty,omitempty"`
+}
+
+// GetIntentLogsParams defines parameters for GetIntentLogs.
+type GetIntentLogsParams struct {
+	// Follow Keep streaming the new log lines until the client disconnects
+	Follow *bool `form:"follow,omitempty" json:"follow,omitempty"`
+
+	// TailLines Number of lines from the end of the logs of each Pod to start from
+	TailLines *int `form:"tail_lines,omitempty" json:"tail_lines,omitempty"`
+
+	// SinceTime Time the log lines are returned from
+	SinceTime *time.Time `form:"since_time,omitempty" json:"since_time,omitempty"`
+
+	// Container Container of the Pods the logs are returned of, defaulting to the workload one
+	Container *string `form:"container,omitempty" json:"container,omitempty"`
+}
+
+// PortForwardIntentParams defines parameters for PortForwardIntent.
+type PortForwardIntentParams struct {
+	// Ports Ports of the Pod to forward
+	Ports []int `form:"ports" json:"ports"`
+
+	// Pod Pod of the workload the ports are forwarded of, defaulting to the first running one
+	Pod *string `form:"pod,omitempty" json:"pod,omitempty"`
+}
+
+// CreateSecretJSONRequestBody defines body for CreateSecret for application/json ContentType.
+type CreateSecretJSONRequestBody = CreateSecretRequest
+
 // CreateTokenJSONRequestBody defines body for CreateToken for application/json ContentType.
 type CreateTokenJSONRequestBody = CreateTokenRequest
 
+// ResumeIntentJSONRequestBody defines body for ResumeIntent for application/json ContentType.
+type ResumeIntentJSONRequestBody = ResumeIntentRequest
+
 // SubmitIntentJSONRequestBody defines body for SubmitIntent for application/json ContentType.
 type SubmitIntentJSONRequestBody = IntentSubmission
 
+// UpdateIntentJSONRequestBody defines body for UpdateIntent for application/json ContentType.
+type UpdateIntentJSONRequestBody = IntentSubmission
+
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
 	// List API tokens
@@ -635,9 +770,36 @@ type ServerInterface interface {
 	// Get intent status
 	// (GET /intents/{intent_id})
 	GetIntentStatus(ctx echo.Context, intentId string) error
+	// Update intent
+	// (PATCH /intents/{intent_id})
+	UpdateIntent(ctx echo.Context, intentId string) error
+	// Stream intent events
+	// (GET /intents/{intent_id}/events)
+	StreamIntentEvents(ctx echo.Context, intentId string) error
+	// Execute a command in the intent workload
+	// (GET /intents/{intent_id}/exec)
+	ExecIntent(ctx echo.Context, intentId string, params ExecIntentParams) error
+	// Get intent logs
+	// (GET /intents/{intent_id}/logs)
+	GetIntentLogs(ctx echo.Context, intentId string, params GetIntentLogsParams) error
+	// Forward ports of the intent workload
+	// (GET /intents/{intent_id}/portforward)
+	PortForwardIntent(ctx echo.Context, intentId string, params PortForwardIntentParams) error
+	// Resume intent
+	// (POST /intents/{intent_id}/resume)
+	ResumeIntent(ctx echo.Context, intentId string) error
 	// Get available GPU resources
 	// (GET /resources)
 	GetAvailableResources(ctx echo.Context) error
+	// List Tenant secrets
+	// (GET /secrets)
+	ListSecrets(ctx echo.Context) error
+	// Create or replace Tenant secret
+	// (POST /secrets)
+	CreateSecret(ctx echo.Context) error
+	// Delete Tenant secret
+	// (DELETE /secrets/{secret_name})
+	DeleteSecret(ctx echo.Context, secretName string) error
 }
 
 // ServerInterfaceWrapper converts echo contexts to parameters.
@@ -743,6 +905,197 @@ func (w *ServerInterfaceWrapper) GetIntentStatus(ctx echo.Context) error {
 	return err
 }
 
+// UpdateIntent converts echo context to params.
+func (w *ServerInterfaceWrapper) UpdateIntent(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "intent_id" -------------
+	var intentId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "intent_id", ctx.Param("intent_id"), &intentId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter intent_id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UpdateIntent(ctx, intentId)
+	return err
+}
+
+// StreamIntentEvents converts echo context to params.
+func (w *ServerInterfaceWrapper) StreamIntentEvents(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "intent_id" -------------
+	var intentId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "intent_id", ctx.Param("intent_id"), &intentId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter intent_id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.StreamIntentEvents(ctx, intentId)
+	return err
+}
+
+// ExecIntent converts echo context to params.
+func (w *ServerInterfaceWrapper) ExecIntent(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "intent_id" -------------
+	var intentId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "intent_id", ctx.Param("intent_id"), &intentId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter intent_id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ExecIntentParams
+	// ------------- Required query parameter "command" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "command", ctx.QueryParams(), &params.Command)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter command: %s", err))
+	}
+
+	// ------------- Optional query parameter "pod" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "pod", ctx.QueryParams(), &params.Pod)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter pod: %s", err))
+	}
+
+	// ------------- Optional query parameter "container" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "container", ctx.QueryParams(), &params.Container)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter container: %s", err))
+	}
+
+	// ------------- Optional query parameter "stdin" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "stdin", ctx.QueryParams(), &params.Stdin)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter stdin: %s", err))
+	}
+
+	// ------------- Optional query parameter "tty" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "tty", ctx.QueryParams(), &params.Tty)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tty: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ExecIntent(ctx, intentId, params)
+	return err
+}
+
+// GetIntentLogs converts echo context to params.
+func (w *ServerInterfaceWrapper) GetIntentLogs(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "intent_id" -------------
+	var intentId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "intent_id", ctx.Param("intent_id"), &intentId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter intent_id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetIntentLogsParams
+	// ------------- Optional query parameter "follow" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "follow", ctx.QueryParams(), &params.Follow)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter follow: %s", err))
+	}
+
+	// ------------- Optional query parameter "tail_lines" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "tail_lines", ctx.QueryParams(), &params.TailLines)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tail_lines: %s", err))
+	}
+
+	// ------------- Optional query parameter "since_time" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "since_time", ctx.QueryParams(), &params.SinceTime)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter since_time: %s", err))
+	}
+
+	// ------------- Optional query parameter "container" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "container", ctx.QueryParams(), &params.Container)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter container: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetIntentLogs(ctx, intentId, params)
+	return err
+}
+
+// PortForwardIntent converts echo context to params.
+func (w *ServerInterfaceWrapper) PortForwardIntent(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "intent_id" -------------
+	var intentId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "intent_id", ctx.Param("intent_id"), &intentId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter intent_id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PortForwardIntentParams
+	// ------------- Required query parameter "ports" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "ports", ctx.QueryParams(), &params.Ports)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter ports: %s", err))
+	}
+
+	// ------------- Optional query parameter "pod" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "pod", ctx.QueryParams(), &params.Pod)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter pod: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PortForwardIntent(ctx, intentId, params)
+	return err
+}
+
+// ResumeIntent converts echo context to params.
+func (w *ServerInterfaceWrapper) ResumeIntent(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "intent_id" -------------
+	var intentId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "intent_id", ctx.Param("intent_id"), &intentId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter intent_id: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ResumeIntent(ctx, intentId)
+	return err
+}
+
 // GetAvailableResources converts echo context to params.
 func (w *ServerInterfaceWrapper) GetAvailableResources(ctx echo.Context) error {
 	var err error
@@ -754,6 +1107,46 @@ func (w *ServerInterfaceWrapper) GetAvailableResources(ctx echo.Context) error {
 	return err
 }
 
+// ListSecrets converts echo context to params.
+func (w *ServerInterfaceWrapper) ListSecrets(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ListSecrets(ctx)
+	return err
+}
+
+// CreateSecret converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateSecret(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.CreateSecret(ctx)
+	return err
+}
+
+// DeleteSecret converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteSecret(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "secret_name" -------------
+	var secretName string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "secret_name", ctx.Param("secret_name"), &secretName, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter secret_name: %s", err))
+	}
+
+	ctx.Set(BearerAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteSecret(ctx, secretName)
+	return err
+}
+
 // This is a simple interface which specifies echo.Route addition functions which
 // are present on both echo.Echo and echo.Group, since we want to allow using
 // either of them for path registration
@@ -789,6 +1182,15 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 	router.POST(baseURL+"/intents", wrapper.SubmitIntent)
 	router.DELETE(baseURL+"/intents/:intent_id", wrapper.CancelIntent)
 	router.GET(baseURL+"/intents/:intent_id", wrapper.GetIntentStatus)
+	router.PATCH(baseURL+"/intents/:intent_id", wrapper.UpdateIntent)
+	router.GET(baseURL+"/intents/:intent_id/events", wrapper.StreamIntentEvents)
+	router.GET(baseURL+"/intents/:intent_id/exec", wrapper.ExecIntent)
+	router.GET(baseURL+"/intents/:intent_id/logs", wrapper.GetIntentLogs)
+	router.GET(baseURL+"/intents/:intent_id/portforward", wrapper.PortForwardIntent)
+	router.POST(baseURL+"/intents/:intent_id/resume", wrapper.ResumeIntent)
 	router.GET(baseURL+"/resources", wrapper.GetAvailableResources)
+	router.GET(baseURL+"/secrets", wrapper.ListSecrets)
+	router.POST(baseURL+"/secrets", wrapper.CreateSecret)
+	router.DELETE(baseURL+"/secrets/:secret_name", wrapper.DeleteSecret)
 
 }
@@ -0,0 +1,203 @@
+// Copyright 2025 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package availability
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	flarev1alpha1 "github.com/clastix/flare-internal/api/v1alpha1"
+)
+
+// horizon is the amount of days the windows are evaluated for, bounding the search upon blackout dates.
+const horizon = 366
+
+var weekdays = map[flarev1alpha1.DayOfWeek]time.Weekday{
+	"Sun": time.Sunday,
+	"Mon": time.Monday,
+	"Tue": time.Tuesday,
+	"Wed": time.Wednesday,
+	"Thu": time.Thursday,
+	"Fri": time.Friday,
+	"Sat": time.Saturday,
+}
+
+// Window is a time range the workload is allowed to run in.
+type Window struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Enabled returns true when the workload is constrained to availability windows.
+func Enabled(availability flarev1alpha1.IntentWorkloadConstraintAvailability) bool {
+	return availability.WindowStart != "" || availability.WindowEnd != "" || len(availability.DaysOfWeek) > 0 ||
+		len(availability.BlackoutDates) > 0 || len(availability.MaintenanceWindows) > 0
+}
+
+// Outside returns true when the workload is held, or suspended, outside its availability windows:
+// the Availability condition is False either when the window is closed, or when no window can be evaluated.
+func Outside(conditions []metav1.Condition) bool {
+	return meta.IsStatusConditionFalse(conditions, flarev1alpha1.IntentStatusTypeAvailability)
+}
+
+// Next returns the availability window containing the given time, or the following one:
+// false is returned when no window opens within the evaluation horizon.
+//
+// Daily windows are evaluated in the given timezone, defaulting to UTC: a window ending before its start
+// spans midnight, and it's bound to the day it starts. A missing start or end means the whole day.
+// Windows starting on a blackout date are skipped, and maintenance windows are carved out of the resulting ones.
+func Next(availability flarev1alpha1.IntentWorkloadConstraintAvailability, now time.Time) (Window, bool, error) {
+	location := time.UTC
+
+	if availability.Timezone != "" {
+		var err error
+		if location, err = time.LoadLocation(availability.Timezone); err != nil {
+			return Window{}, false, errors.Wrap(err, "cannot load availability timezone")
+		}
+	}
+
+	startHour, startMinute, err := clock(availability.WindowStart)
+	if err != nil {
+		return Window{}, false, err
+	}
+
+	endHour, endMinute, err := clock(availability.WindowEnd)
+	if err != nil {
+		return Window{}, false, err
+	}
+
+	days := sets.New[time.Weekday]()
+	for _, day := range availability.DaysOfWeek {
+		days.Insert(weekdays[day])
+	}
+
+	blackoutDates := sets.New[string](availability.BlackoutDates...)
+
+	local := now.In(location)
+
+	windows := make([]Window, 0, horizon+2)
+	// Starting from the previous day, since its window could span midnight.
+	for offset := -1; offset <= horizon; offset++ {
+		day := time.Date(local.Year(), local.Month(), local.Day()+offset, 0, 0, 0, 0, location)
+
+		if days.Len() > 0 && !days.Has(day.Weekday()) {
+			continue
+		}
+
+		if blackoutDates.Has(day.Format(time.DateOnly)) {
+			continue
+		}
+
+		window := Window{
+			Start: time.Date(day.Year(), day.Month(), day.Day(), startHour, startMinute, 0, 0, location),
+			End:   time.Date(day.Year(), day.Month(), day.Day(), endHour, endMinute, 0, 0, location),
+		}
+
+		if !window.End.After(window.Start) {
+			window.End = window.End.AddDate(0, 0, 1)
+		}
+
+		windows = merge(windows, window)
+	}
+
+	if len(windows) == 0 {
+		return Window{}, false, nil
+	}
+
+	for _, maintenance := range maintenances(availability.MaintenanceWindows, windows[0].Start, windows[len(windows)-1].End) {
+		windows = subtract(windows, maintenance)
+	}
+
+	for _, window := range windows {
+		if window.End.After(now) {
+			return window, true, nil
+		}
+	}
+
+	return Window{}, false, nil
+}
+
+// clock parses the HH:MM time of the day, an empty value is the midnight.
+func clock(value string) (int, int, error) {
+	if value == "" {
+		return 0, 0, nil
+	}
+
+	var hour, minute int
+	if _, err := fmt.Sscanf(value, "%d:%d", &hour, &minute); err != nil {
+		return 0, 0, errors.Wrapf(err, "cannot parse availability window time %q", value)
+	}
+
+	return hour, minute, nil
+}
+
+// merge appends the window to the sorted ones, joining it with the last one when they're contiguous.
+func merge(windows []Window, window Window) []Window {
+	if last := len(windows) - 1; last >= 0 && !window.Start.After(windows[last].End) {
+		if window.End.After(windows[last].End) {
+			windows[last].End = window.End
+		}
+
+		return windows
+	}
+
+	return append(windows, window)
+}
+
+// subtract removes the given range from the sorted windows, splitting the overlapping ones.
+func subtract(windows []Window, removed Window) []Window {
+	result := make([]Window, 0, len(windows)+1)
+
+	for _, window := range windows {
+		if !removed.End.After(window.Start) || !removed.Start.Before(window.End) {
+			result = append(result, window)
+
+			continue
+		}
+
+		if window.Start.Before(removed.Start) {
+			result = append(result, Window{Start: window.Start, End: removed.Start})
+		}
+
+		if removed.End.Before(window.End) {
+			result = append(result, Window{Start: removed.End, End: window.End})
+		}
+	}
+
+	return result
+}
+
+// maintenances returns the occurrences of the maintenance windows overlapping the given range.
+func maintenances(windows []flarev1alpha1.IntentConstraintAvailabilityMaintenanceWindow, from, to time.Time) []Window {
+	var occurrences []Window
+
+	for _, window := range windows {
+		duration := window.End.Sub(window.Start.Time)
+		if duration <= 0 {
+			continue
+		}
+
+		for occurrence := 0; ; occurrence++ {
+			start := window.Start.AddDate(0, 0, 7*occurrence)
+			if window.Frequency == "Monthly" {
+				start = window.Start.AddDate(0, occurrence, 0)
+			}
+
+			if start.After(to) {
+				break
+			}
+
+			if end := start.Add(duration); end.After(from) {
+				occurrences = append(occurrences, Window{Start: start, End: end})
+			}
+		}
+	}
+
+	return occurrences
+}
@@ -0,0 +1,219 @@
+// Copyright 2025 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package availability
+
+import (
+	"testing"
+	"time"
+	// Embedding the timezone database, the evaluation must not depend on the host one.
+	_ "time/tzdata"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	flarev1alpha1 "github.com/clastix/flare-internal/api/v1alpha1"
+)
+
+func TestNext(t *testing.T) {
+	// Wednesday, before the daylight saving time starts in Europe, and after it started in the United States.
+	now := time.Date(2025, time.March, 12, 10, 0, 0, 0, time.UTC)
+
+	utc := func(value string) time.Time {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			t.Fatalf("cannot parse %q: %s", value, err)
+		}
+
+		return parsed
+	}
+
+	maintenance := func(start, end, frequency string) flarev1alpha1.IntentConstraintAvailabilityMaintenanceWindow {
+		return flarev1alpha1.IntentConstraintAvailabilityMaintenanceWindow{
+			Start:     metav1.NewTime(utc(start)),
+			End:       metav1.NewTime(utc(end)),
+			Frequency: frequency,
+		}
+	}
+
+	tests := []struct {
+		name         string
+		availability flarev1alpha1.IntentWorkloadConstraintAvailability
+		want         Window
+		found        bool
+		err          bool
+	}{
+		{
+			name:         "open window",
+			availability: flarev1alpha1.IntentWorkloadConstraintAvailability{WindowStart: "09:00", WindowEnd: "17:00"},
+			want:         Window{Start: utc("2025-03-12T09:00:00Z"), End: utc("2025-03-12T17:00:00Z")},
+			found:        true,
+		},
+		{
+			name:         "window opening later in the day",
+			availability: flarev1alpha1.IntentWorkloadConstraintAvailability{WindowStart: "11:30", WindowEnd: "12:45"},
+			want:         Window{Start: utc("2025-03-12T11:30:00Z"), End: utc("2025-03-12T12:45:00Z")},
+			found:        true,
+		},
+		{
+			name:         "window closed for the day",
+			availability: flarev1alpha1.IntentWorkloadConstraintAvailability{WindowStart: "06:00", WindowEnd: "08:00"},
+			want:         Window{Start: utc("2025-03-13T06:00:00Z"), End: utc("2025-03-13T08:00:00Z")},
+			found:        true,
+		},
+		{
+			name:         "window across midnight opening tonight",
+			availability: flarev1alpha1.IntentWorkloadConstraintAvailability{WindowStart: "22:00", WindowEnd: "06:00"},
+			want:         Window{Start: utc("2025-03-12T22:00:00Z"), End: utc("2025-03-13T06:00:00Z")},
+			found:        true,
+		},
+		{
+			name:         "window across midnight opened yesterday",
+			availability: flarev1alpha1.IntentWorkloadConstraintAvailability{WindowStart: "20:00", WindowEnd: "11:00"},
+			want:         Window{Start: utc("2025-03-11T20:00:00Z"), End: utc("2025-03-12T11:00:00Z")},
+			found:        true,
+		},
+		{
+			name:         "whole day windows are merged",
+			availability: flarev1alpha1.IntentWorkloadConstraintAvailability{DaysOfWeek: []flarev1alpha1.DayOfWeek{"Wed", "Thu"}},
+			want:         Window{Start: utc("2025-03-12T00:00:00Z"), End: utc("2025-03-14T00:00:00Z")},
+			found:        true,
+		},
+		{
+			name:         "timezone ahead of UTC",
+			availability: flarev1alpha1.IntentWorkloadConstraintAvailability{WindowStart: "09:00", WindowEnd: "17:00", Timezone: "Europe/Rome"},
+			want:         Window{Start: utc("2025-03-12T08:00:00Z"), End: utc("2025-03-12T16:00:00Z")},
+			found:        true,
+		},
+		{
+			name:         "timezone behind UTC in daylight saving time",
+			availability: flarev1alpha1.IntentWorkloadConstraintAvailability{WindowStart: "09:00", WindowEnd: "17:00", Timezone: "America/New_York"},
+			want:         Window{Start: utc("2025-03-12T13:00:00Z"), End: utc("2025-03-12T21:00:00Z")},
+			found:        true,
+		},
+		{
+			name:         "timezone already in the next day",
+			availability: flarev1alpha1.IntentWorkloadConstraintAvailability{WindowStart: "09:00", WindowEnd: "17:00", Timezone: "Asia/Tokyo"},
+			want:         Window{Start: utc("2025-03-13T00:00:00Z"), End: utc("2025-03-13T08:00:00Z")},
+			found:        true,
+		},
+		{
+			name: "days of week",
+			availability: flarev1alpha1.IntentWorkloadConstraintAvailability{
+				WindowStart: "09:00",
+				WindowEnd:   "17:00",
+				DaysOfWeek:  []flarev1alpha1.DayOfWeek{"Mon", "Fri"},
+			},
+			want:  Window{Start: utc("2025-03-14T09:00:00Z"), End: utc("2025-03-14T17:00:00Z")},
+			found: true,
+		},
+		{
+			name: "days of week in the given timezone",
+			availability: flarev1alpha1.IntentWorkloadConstraintAvailability{
+				WindowStart: "01:00",
+				WindowEnd:   "03:00",
+				Timezone:    "Asia/Tokyo",
+				DaysOfWeek:  []flarev1alpha1.DayOfWeek{"Thu"},
+			},
+			want:  Window{Start: utc("2025-03-12T16:00:00Z"), End: utc("2025-03-12T18:00:00Z")},
+			found: true,
+		},
+		{
+			name: "blackout date",
+			availability: flarev1alpha1.IntentWorkloadConstraintAvailability{
+				WindowStart:   "09:00",
+				WindowEnd:     "17:00",
+				BlackoutDates: []string{"2025-03-12", "2025-03-13"},
+			},
+			want:  Window{Start: utc("2025-03-14T09:00:00Z"), End: utc("2025-03-14T17:00:00Z")},
+			found: true,
+		},
+		{
+			name: "blackout date of a window across midnight",
+			availability: flarev1alpha1.IntentWorkloadConstraintAvailability{
+				WindowStart:   "20:00",
+				WindowEnd:     "11:00",
+				BlackoutDates: []string{"2025-03-11"},
+			},
+			want:  Window{Start: utc("2025-03-12T20:00:00Z"), End: utc("2025-03-13T11:00:00Z")},
+			found: true,
+		},
+		{
+			name: "weekly maintenance splitting the window",
+			availability: flarev1alpha1.IntentWorkloadConstraintAvailability{
+				WindowStart:        "09:00",
+				WindowEnd:          "17:00",
+				MaintenanceWindows: []flarev1alpha1.IntentConstraintAvailabilityMaintenanceWindow{maintenance("2025-03-05T09:30:00Z", "2025-03-05T12:00:00Z", "Weekly")},
+			},
+			want:  Window{Start: utc("2025-03-12T12:00:00Z"), End: utc("2025-03-12T17:00:00Z")},
+			found: true,
+		},
+		{
+			name: "weekly maintenance not recurring on the day",
+			availability: flarev1alpha1.IntentWorkloadConstraintAvailability{
+				WindowStart:        "09:00",
+				WindowEnd:          "17:00",
+				MaintenanceWindows: []flarev1alpha1.IntentConstraintAvailabilityMaintenanceWindow{maintenance("2025-03-06T09:30:00Z", "2025-03-06T12:00:00Z", "Weekly")},
+			},
+			want:  Window{Start: utc("2025-03-12T09:00:00Z"), End: utc("2025-03-12T17:00:00Z")},
+			found: true,
+		},
+		{
+			name: "monthly maintenance covering the window",
+			availability: flarev1alpha1.IntentWorkloadConstraintAvailability{
+				WindowStart:        "09:00",
+				WindowEnd:          "17:00",
+				MaintenanceWindows: []flarev1alpha1.IntentConstraintAvailabilityMaintenanceWindow{maintenance("2025-01-12T08:00:00Z", "2025-01-12T18:00:00Z", "Monthly")},
+			},
+			want:  Window{Start: utc("2025-03-13T09:00:00Z"), End: utc("2025-03-13T17:00:00Z")},
+			found: true,
+		},
+		{
+			name: "maintenance starting in the future",
+			availability: flarev1alpha1.IntentWorkloadConstraintAvailability{
+				WindowStart:        "09:00",
+				WindowEnd:          "17:00",
+				MaintenanceWindows: []flarev1alpha1.IntentConstraintAvailabilityMaintenanceWindow{maintenance("2025-03-19T08:00:00Z", "2025-03-19T18:00:00Z", "Weekly")},
+			},
+			want:  Window{Start: utc("2025-03-12T09:00:00Z"), End: utc("2025-03-12T17:00:00Z")},
+			found: true,
+		},
+		{
+			name: "no window within the horizon",
+			availability: flarev1alpha1.IntentWorkloadConstraintAvailability{
+				WindowStart:        "09:00",
+				WindowEnd:          "17:00",
+				MaintenanceWindows: []flarev1alpha1.IntentConstraintAvailabilityMaintenanceWindow{maintenance("2025-01-01T00:00:00Z", "2027-01-01T00:00:00Z", "Weekly")},
+			},
+			found: false,
+		},
+		{
+			name:         "unknown timezone",
+			availability: flarev1alpha1.IntentWorkloadConstraintAvailability{WindowStart: "09:00", WindowEnd: "17:00", Timezone: "Mars/Olympus_Mons"},
+			err:          true,
+		},
+		{
+			name:         "invalid window time",
+			availability: flarev1alpha1.IntentWorkloadConstraintAvailability{WindowStart: "nine", WindowEnd: "17:00"},
+			err:          true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			window, found, err := Next(tt.availability, now)
+			if (err != nil) != tt.err {
+				t.Fatalf("Next() error = %v, expected error %t", err, tt.err)
+			}
+
+			if found != tt.found {
+				t.Fatalf("Next() found = %t, expected %t", found, tt.found)
+			}
+
+			if !window.Start.Equal(tt.want.Start) || !window.End.Equal(tt.want.End) {
+				t.Errorf("Next() = [%s, %s], expected [%s, %s]",
+					window.Start.UTC().Format(time.RFC3339), window.End.UTC().Format(time.RFC3339),
+					tt.want.Start.UTC().Format(time.RFC3339), tt.want.End.UTC().Format(time.RFC3339))
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2025 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	flarev1alpha1 "github.com/clastix/flare-internal/api/v1alpha1"
+)
+
+// HandleBudget tracks the accrued cost against the maximum total cost of the Intent:
+// a warning is reported once a threshold is crossed, and the workload is suspended when the budget is exhausted.
+func (i *IntentReconciler) HandleBudget(ctx context.Context, intent *flarev1alpha1.Intent) error {
+	budget := intent.Spec.Constraints.MaxTotalCost
+	if budget <= 0 {
+		if meta.FindStatusCondition(intent.Status.Conditions, flarev1alpha1.IntentStatusTypeBudget) == nil {
+			return nil
+		}
+
+		return UpdateStatus(ctx, i.Client, intent, func(status *flarev1alpha1.IntentStatus) {
+			meta.RemoveStatusCondition(&status.Conditions, flarev1alpha1.IntentStatusTypeBudget)
+		})
+	}
+	// The accrued cost is used rather than the extrapolated one, it changes at most once per metering interval:
+	// updating the condition message at every run would trigger a new reconciliation.
+	accrued := intent.Status.Cost.Accrued
+	spent := fmt.Sprintf("%.2f of %.2f EUR spent", accrued, budget)
+
+	var threshold int
+
+	for _, t := range i.BudgetWarningThresholds {
+		if accrued*100 >= budget*float64(t) {
+			threshold = max(threshold, t)
+		}
+	}
+
+	condition := metav1.Condition{
+		Type:               flarev1alpha1.IntentStatusTypeBudget,
+		ObservedGeneration: intent.Generation,
+	}
+
+	switch {
+	case accrued >= budget:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = flarev1alpha1.IntentBudgetReasonExceeded
+		condition.Message = spent + ", the workload has been suspended until the budget is raised"
+	case threshold > 0:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = flarev1alpha1.IntentBudgetReasonWarning
+		condition.Message = fmt.Sprintf("%s, the %d%% warning threshold has been reached", spent, threshold)
+	default:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = flarev1alpha1.IntentBudgetReasonWithin
+		condition.Message = spent
+	}
+
+	return UpdateStatusCondition(ctx, i.Client, intent, condition)
+}
+
+// budgetExceeded returns true when the workload must be suspended since the Intent budget is exhausted.
+func budgetExceeded(intent *flarev1alpha1.Intent) bool {
+	condition := meta.FindStatusCondition(intent.Status.Conditions, flarev1alpha1.IntentStatusTypeBudget)
+
+	return condition != nil && condition.Reason == flarev1alpha1.IntentBudgetReasonExceeded
+}
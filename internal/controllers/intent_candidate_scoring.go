@@ -0,0 +1,185 @@
+// Copyright 2025 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"slices"
+	"strings"
+
+	fluidosv1alpha1 "github.com/fluidos-project/node/apis/advertisement/v1alpha1"
+	fluidosnodev1alpha1 "github.com/fluidos-project/node/apis/nodecore/v1alpha1"
+
+	flarev1alpha1 "github.com/clastix/flare-internal/api/v1alpha1"
+	"github.com/clastix/flare-internal/internal/flavor"
+)
+
+// candidateMetric is a Flavor characteristic the PeeringCandidates are ranked upon.
+type candidateMetric struct {
+	name string
+	// value returns the advertised characteristic, false when it's not advertised.
+	value func(flavor fluidosnodev1alpha1.Flavor) (float64, bool)
+	// lowerIsBetter is true when the lowest value is the best one, such as the price.
+	lowerIsBetter bool
+}
+
+var (
+	priceMetric = candidateMetric{
+		name:          "price",
+		value:         flavor.HourlyRate,
+		lowerIsBetter: true,
+	}
+	latencyMetric = candidateMetric{
+		name: "latency",
+		value: func(f fluidosnodev1alpha1.Flavor) (float64, bool) {
+			return flavor.GPUNumber(f, "latency_ms")
+		},
+		lowerIsBetter: true,
+	}
+	powerUsageEffectivenessMetric = candidateMetric{
+		name: "pue",
+		value: func(f fluidosnodev1alpha1.Flavor) (float64, bool) {
+			return flavor.GPUNumber(f, "pue")
+		},
+		lowerIsBetter: true,
+	}
+	renewableEnergyMetric = candidateMetric{
+		name: "renewable",
+		value: func(f fluidosnodev1alpha1.Flavor) (float64, bool) {
+			renewable, ok := flavor.GPUBool(f, "renewable_energy")
+			if !ok {
+				return 0, false
+			}
+
+			if renewable {
+				return 1, true
+			}
+
+			return 0, true
+		},
+	}
+	carbonMetric = candidateMetric{
+		name:          "carbon",
+		value:         flavor.EmissionRate,
+		lowerIsBetter: true,
+	}
+	performanceMetric = candidateMetric{
+		name: "performance",
+		value: func(f fluidosnodev1alpha1.Flavor) (float64, bool) {
+			return flavor.GPUNumber(f, "fp32_tflops")
+		},
+	}
+)
+
+type metricWeight struct {
+	metric candidateMetric
+	weight float64
+}
+
+// candidateScoring is the scoring function of an Intent objective,
+// as the weighted sum of the normalized metrics the PeeringCandidates are ranked upon.
+type candidateScoring []metricWeight
+
+// objectiveScorings maps the Intent objectives to their scoring function:
+// new objectives are supported by registering their metrics weights.
+var objectiveScorings = map[flarev1alpha1.IntentObject]candidateScoring{
+	flarev1alpha1.IntentObjectCostMinimization: {
+		{metric: priceMetric, weight: 1},
+	},
+	flarev1alpha1.IntentObjectLatencyMinimization: {
+		{metric: latencyMetric, weight: 1},
+	},
+	flarev1alpha1.IntentObjectEnergyEfficiency: {
+		{metric: carbonMetric, weight: 0.5},
+		{metric: powerUsageEffectivenessMetric, weight: 0.25},
+		{metric: renewableEnergyMetric, weight: 0.25},
+	},
+	flarev1alpha1.IntentObjectPerformanceMaximization: {
+		{metric: performanceMetric, weight: 1},
+	},
+	flarev1alpha1.IntentObjectBalancedOptimization: {
+		{metric: priceMetric, weight: 0.25},
+		{metric: latencyMetric, weight: 0.25},
+		{metric: performanceMetric, weight: 0.25},
+		{metric: powerUsageEffectivenessMetric, weight: 0.125},
+		{metric: renewableEnergyMetric, weight: 0.125},
+	},
+}
+
+// scoredCandidate is a PeeringCandidate along with its score, and the normalized value of each metric.
+type scoredCandidate struct {
+	candidate fluidosv1alpha1.PeeringCandidate
+	score     float64
+	breakdown map[string]float64
+}
+
+// rankCandidates scores the PeeringCandidates upon the Intent objective, sorting them from the best one.
+// Each metric is normalized between 0 and 1 among the candidates, the ones not advertising it score 0:
+// ties are broken upon the PeeringCandidate name.
+func rankCandidates(objective flarev1alpha1.IntentObject, candidates []fluidosv1alpha1.PeeringCandidate) []scoredCandidate {
+	scoring, ok := objectiveScorings[objective]
+	if !ok {
+		scoring = objectiveScorings[flarev1alpha1.IntentObjectBalancedOptimization]
+	}
+
+	ranked := make([]scoredCandidate, 0, len(candidates))
+	for _, candidate := range candidates {
+		ranked = append(ranked, scoredCandidate{candidate: candidate, breakdown: map[string]float64{}})
+	}
+
+	for _, weighted := range scoring {
+		metric := weighted.metric
+
+		values := make([]float64, len(ranked))
+		advertised := make([]bool, len(ranked))
+
+		var (
+			minValue, maxValue float64
+			found              bool
+		)
+
+		for index := range ranked {
+			values[index], advertised[index] = metric.value(ranked[index].candidate.Spec.Flavor)
+
+			switch {
+			case !advertised[index]:
+				continue
+			case !found:
+				minValue, maxValue, found = values[index], values[index], true
+			default:
+				minValue, maxValue = min(minValue, values[index]), max(maxValue, values[index])
+			}
+		}
+
+		for index := range ranked {
+			var normalized float64
+
+			switch {
+			case !advertised[index]:
+				normalized = 0
+			case maxValue == minValue:
+				normalized = 1
+			case metric.lowerIsBetter:
+				normalized = (maxValue - values[index]) / (maxValue - minValue)
+			default:
+				normalized = (values[index] - minValue) / (maxValue - minValue)
+			}
+
+			ranked[index].breakdown[metric.name] = normalized
+			ranked[index].score += weighted.weight * normalized
+		}
+	}
+
+	slices.SortStableFunc(ranked, func(a, b scoredCandidate) int {
+		switch {
+		case a.score > b.score:
+			return -1
+		case a.score < b.score:
+			return 1
+		default:
+			return strings.Compare(a.candidate.Name, b.candidate.Name)
+		}
+	})
+
+	return ranked
+}
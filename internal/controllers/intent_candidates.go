@@ -0,0 +1,310 @@
+// Copyright 2025 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	fluidosv1alpha1 "github.com/fluidos-project/node/apis/advertisement/v1alpha1"
+	fluidosnodev1alpha1 "github.com/fluidos-project/node/apis/nodecore/v1alpha1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	flarev1alpha1 "github.com/clastix/flare-internal/api/v1alpha1"
+	"github.com/clastix/flare-internal/internal/flavor"
+)
+
+// candidateFilter eliminates the PeeringCandidates whose Flavor is not satisfying an Intent constraint.
+type candidateFilter struct {
+	constraint string
+	match      func(flavor fluidosnodev1alpha1.Flavor) bool
+}
+
+// candidateFilters translates the Intent location and compliance constraints into PeeringCandidate filters,
+// since the FLUIDOS K8Slice selector has no support for them.
+func candidateFilters(intent *flarev1alpha1.Intent) []candidateFilter {
+	constraints := intent.Spec.Constraints
+
+	var filters []candidateFilter
+
+	if len(constraints.Providers) > 0 {
+		filters = append(filters, candidateFilter{
+			constraint: "providers " + strings.Join(constraints.Providers, ", "),
+			match: func(f fluidosnodev1alpha1.Flavor) bool {
+				return slices.ContainsFunc(constraints.Providers, func(provider string) bool {
+					return strings.EqualFold(provider, flavor.Provider(f))
+				})
+			},
+		})
+	}
+
+	if constraints.Location != "" && !strings.EqualFold(constraints.Location, "any") {
+		filters = append(filters, candidateFilter{
+			constraint: "location " + constraints.Location,
+			match: func(f fluidosnodev1alpha1.Flavor) bool {
+				return flavor.InLocation(f, constraints.Location)
+			},
+		})
+	}
+
+	if constraints.AvailabilityZone != "" {
+		filters = append(filters, candidateFilter{
+			constraint: "availability zone " + constraints.AvailabilityZone,
+			match: func(f fluidosnodev1alpha1.Flavor) bool {
+				return strings.EqualFold(constraints.AvailabilityZone, flavor.AvailabilityZone(f))
+			},
+		})
+	}
+
+	for _, residency := range constraints.Compliance.DataResidency {
+		filters = append(filters, candidateFilter{
+			constraint: "data residency " + residency,
+			match: func(f fluidosnodev1alpha1.Flavor) bool {
+				return flavor.InLocation(f, residency)
+			},
+		})
+	}
+
+	for _, certification := range constraints.Compliance.Certifications {
+		filters = append(filters, candidateFilter{
+			constraint: "certification " + string(certification),
+			match: func(f fluidosnodev1alpha1.Flavor) bool {
+				return slices.ContainsFunc(flavor.Certifications(f), func(advertised string) bool {
+					return strings.EqualFold(advertised, string(certification))
+				})
+			},
+		})
+	}
+
+	return append(filters, energyFilters(constraints.Energy)...)
+}
+
+// energyFilters translates the Intent energy constraints into PeeringCandidate filters:
+// a Flavor not advertising the required characteristic is eliminated, except for the PUE
+// whose maximum is always defaulted.
+func energyFilters(energy flarev1alpha1.IntentWorkloadConstraintEnergy) []candidateFilter {
+	var filters []candidateFilter
+
+	if energy.RenewableEnergyOnly {
+		filters = append(filters, candidateFilter{
+			constraint: "renewable energy",
+			match: func(f fluidosnodev1alpha1.Flavor) bool {
+				renewable, _ := flavor.GPUBool(f, "renewable_energy")
+
+				return renewable
+			},
+		})
+	}
+
+	if energy.GreenCertifiedOnly {
+		filters = append(filters, candidateFilter{
+			constraint: "green certification",
+			match: func(f fluidosnodev1alpha1.Flavor) bool {
+				certified, _ := flavor.GPUBool(f, "green_certified")
+
+				return certified
+			},
+		})
+	}
+
+	if energy.EnergyEfficiencyRating != "" {
+		filters = append(filters, candidateFilter{
+			constraint: "energy efficiency rating " + energy.EnergyEfficiencyRating,
+			match: func(f fluidosnodev1alpha1.Flavor) bool {
+				rating := flavor.EnergyEfficiencyRating(f)
+				// Ratings are ordered from A, the highest, to F.
+				return len(rating) == 1 && rating >= "A" && rating <= strings.ToUpper(energy.EnergyEfficiencyRating)
+			},
+		})
+	}
+
+	if energy.PowerUsageEffectiveness > 0 {
+		filters = append(filters, candidateFilter{
+			constraint: fmt.Sprintf("power usage effectiveness %.2f", energy.PowerUsageEffectiveness),
+			match: func(f fluidosnodev1alpha1.Flavor) bool {
+				pue, ok := flavor.GPUNumber(f, "pue")
+
+				return !ok || pue <= float64(energy.PowerUsageEffectiveness)
+			},
+		})
+	}
+
+	// The carbon footprint format is validated upon the Intent submission.
+	if footprint, err := flavor.ParseCarbonFootprint(energy.MaxCarbonFootprint); err == nil {
+		filters = append(filters, candidateFilter{
+			constraint: "carbon footprint " + energy.MaxCarbonFootprint,
+			match: func(f fluidosnodev1alpha1.Flavor) bool {
+				rate, ok := flavor.EmissionRate(f)
+
+				return ok && rate <= footprint
+			},
+		})
+	}
+
+	return filters
+}
+
+// filterCandidates returns the PeeringCandidates satisfying all the Intent constraints:
+// when none is left, the constraint which eliminated the last of them is returned.
+func filterCandidates(intent *flarev1alpha1.Intent, candidates []fluidosv1alpha1.PeeringCandidate) ([]fluidosv1alpha1.PeeringCandidate, string) {
+	for _, filter := range candidateFilters(intent) {
+		candidates = slices.DeleteFunc(candidates, func(candidate fluidosv1alpha1.PeeringCandidate) bool {
+			return !filter.match(candidate.Spec.Flavor)
+		})
+
+		if len(candidates) == 0 {
+			return nil, filter.constraint
+		}
+	}
+
+	return candidates, ""
+}
+
+// candidateSelection is the outcome of the PeeringCandidate selection, reported in the Solver condition.
+type candidateSelection struct {
+	// candidate is the selected PeeringCandidate, nil when none has been accepted.
+	candidate   *flarev1alpha1.IntentCandidate
+	negotiation *flarev1alpha1.IntentNegotiation
+	reason      string
+	message     string
+}
+
+//+kubebuilder:rbac:groups=advertisement.fluidos.eu,resources=peeringcandidates,verbs=get;list;watch;patch
+//+kubebuilder:rbac:groups=nodecore.fluidos.eu,resources=solvers,verbs=patch;delete
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// selectCandidate negotiates the PeeringCandidates discovered by the Solver satisfying the Intent constraints,
+// and picks the best accepted one upon the Intent objective: the Solver is not interested anymore in the other ones,
+// and it's enabled to reserve the selected one, and peer with it.
+// When no offer is accepted, the negotiation fallback strategy is applied: the negotiation is not performed again
+// until the Intent is updated, or the queue deletes the Solver for a new discovery once the negotiation timeout expires.
+// It returns nil when the last negotiation outcome is still in place.
+func (i *IntentReconciler) selectCandidate(ctx context.Context, intent *flarev1alpha1.Intent, solver *fluidosnodev1alpha1.Solver, last *flarev1alpha1.IntentNegotiation) (*candidateSelection, error) {
+	if last != nil && last.ObservedGeneration == intent.Generation && last.Outcome != flarev1alpha1.IntentNegotiationReasonAccepted {
+		return nil, nil
+	}
+
+	var candidateList fluidosv1alpha1.PeeringCandidateList
+	if err := i.Client.List(ctx, &candidateList); err != nil {
+		return nil, errors.Wrap(err, "cannot retrieve PeeringCandidates")
+	}
+
+	discovered := make([]fluidosv1alpha1.PeeringCandidate, 0, len(candidateList.Items))
+
+	for _, candidate := range candidateList.Items {
+		if candidate.Spec.Available && slices.Contains(candidate.Spec.InterestedSolverIDs, solver.Name) {
+			discovered = append(discovered, candidate)
+		}
+	}
+
+	if len(discovered) == 0 {
+		return &candidateSelection{reason: "SolverFailed", message: "Solver " + solver.Name + " has discovered no available PeeringCandidate"}, nil
+	}
+
+	candidates, constraint := filterCandidates(intent, slices.Clone(discovered))
+	if len(candidates) == 0 {
+		return &candidateSelection{reason: "SolverFailed", message: fmt.Sprintf("none of the %d discovered PeeringCandidates satisfies the %s constraint", len(discovered), constraint)}, nil
+	}
+
+	outcome := i.negotiate(intent, candidates)
+
+	negotiation := &flarev1alpha1.IntentNegotiation{
+		Rounds:             outcome.rounds,
+		ObservedGeneration: intent.Generation,
+		Time:               metav1.Now(),
+	}
+
+	if len(outcome.accepted) == 0 {
+		return i.negotiationFallback(intent, outcome, negotiation), nil
+	}
+
+	ranked := rankCandidates(intent.Spec.Objective, outcome.accepted)
+	selected := ranked[0].candidate
+
+	for index := range discovered {
+		candidate := &discovered[index]
+		if candidate.Name == selected.Name {
+			continue
+		}
+
+		// PeeringCandidates are shared among the Solvers, the interested ones must not be overwritten.
+		patch := client.MergeFromWithOptions(candidate.DeepCopy(), client.MergeFromWithOptimisticLock{})
+		candidate.Spec.InterestedSolverIDs = slices.DeleteFunc(candidate.Spec.InterestedSolverIDs, func(id string) bool {
+			return id == solver.Name
+		})
+
+		if err := i.Client.Patch(ctx, candidate, patch); err != nil {
+			return nil, errors.Wrap(err, "cannot discard PeeringCandidate "+candidate.Name)
+		}
+	}
+
+	patch := client.MergeFrom(solver.DeepCopy())
+	solver.Spec.ReserveAndBuy = true
+	solver.Spec.EstablishPeering = true
+
+	if err := i.Client.Patch(ctx, solver, patch); err != nil {
+		return nil, errors.Wrap(err, "cannot enable the Solver reservation")
+	}
+
+	scored := &flarev1alpha1.IntentCandidate{
+		Name:      selected.Name,
+		Objective: intent.Spec.Objective,
+		Score:     ranked[0].score,
+		Breakdown: ranked[0].breakdown,
+		Round:     outcome.rounds,
+		Deviation: outcome.deviations[selected.Name],
+	}
+
+	negotiation.Outcome = flarev1alpha1.IntentNegotiationReasonAccepted
+
+	i.Recorder.Eventf(intent, corev1.EventTypeNormal, flarev1alpha1.IntentNegotiationReasonAccepted, "PeeringCandidate %s has been accepted in round %d with %.0f%% deviation",
+		selected.Name, scored.Round, scored.Deviation*100)
+
+	return &candidateSelection{
+		candidate:   scored,
+		negotiation: negotiation,
+		reason:      "CandidateSelected",
+		message:     fmt.Sprintf("PeeringCandidate %s has been selected among %d discovered ones with score %.2f, waiting for its reservation", selected.Name, len(discovered), scored.Score),
+	}, nil
+}
+
+// negotiationFallback applies the Intent fallback strategy when no offer has been accepted in the negotiation rounds:
+// unknown strategies are handled as the queue one.
+func (i *IntentReconciler) negotiationFallback(intent *flarev1alpha1.Intent, outcome negotiationOutcome, negotiation *flarev1alpha1.IntentNegotiation) *candidateSelection {
+	closest := fmt.Sprintf("the closest offer is PeeringCandidate %s with %.0f%% deviation", outcome.closest.Name, outcome.deviations[outcome.closest.Name]*100)
+	if rate, ok := flavor.HourlyRate(outcome.closest.Spec.Flavor); ok {
+		closest += fmt.Sprintf(" at %.2f EUR/h", rate)
+	}
+
+	var message string
+
+	switch intent.Spec.Constraints.Negotiation.FallbackStrategy {
+	case negotiationFallbackFail:
+		negotiation.Outcome = flarev1alpha1.IntentNegotiationReasonFailed
+		message = fmt.Sprintf("no offer has been accepted in %d negotiation rounds, %s", outcome.rounds, closest)
+
+		i.Recorder.Event(intent, corev1.EventTypeWarning, negotiation.Outcome, message)
+	case negotiationFallbackNotify:
+		negotiation.Outcome = flarev1alpha1.IntentNegotiationReasonApprovalRequired
+		message = fmt.Sprintf("no offer has been accepted in %d negotiation rounds, %s: update the Intent constraints to accept it", outcome.rounds, closest)
+
+		i.Recorder.Event(intent, corev1.EventTypeWarning, negotiation.Outcome, message)
+	default:
+		negotiation.Outcome = flarev1alpha1.IntentNegotiationReasonQueued
+		message = fmt.Sprintf("no offer has been accepted in %d negotiation rounds, %s: a new discovery is queued in %s", outcome.rounds, closest, negotiationTimeout(intent))
+
+		i.Recorder.Event(intent, corev1.EventTypeNormal, negotiation.Outcome, message)
+	}
+
+	return &candidateSelection{
+		negotiation: negotiation,
+		reason:      negotiation.Outcome,
+		message:     message,
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2025 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	flarev1alpha1 "github.com/clastix/flare-internal/api/v1alpha1"
+	"github.com/clastix/flare-internal/internal/availability"
+)
+
+// HandleAvailability schedules the workload upon its availability windows, tracking the current or the next one
+// in the Intent status: outside the windows, the workload is held or suspended.
+// It returns the time left before the window opens, or closes.
+func (i *IntentReconciler) HandleAvailability(ctx context.Context, intent *flarev1alpha1.Intent) (time.Duration, error) {
+	constraint := intent.Spec.Constraints.Availability
+
+	if !availability.Enabled(constraint) {
+		if meta.FindStatusCondition(intent.Status.Conditions, flarev1alpha1.IntentStatusTypeAvailability) == nil && intent.Status.NextWindow == nil {
+			return 0, nil
+		}
+
+		return 0, UpdateStatus(ctx, i.Client, intent, func(status *flarev1alpha1.IntentStatus) {
+			meta.RemoveStatusCondition(&status.Conditions, flarev1alpha1.IntentStatusTypeAvailability)
+			status.NextWindow = nil
+		})
+	}
+
+	now := time.Now()
+
+	window, found, err := availability.Next(constraint, now)
+
+	condition := metav1.Condition{
+		Type:               flarev1alpha1.IntentStatusTypeAvailability,
+		ObservedGeneration: intent.Generation,
+		Status:             metav1.ConditionFalse,
+		Reason:             flarev1alpha1.IntentAvailabilityReasonNoWindow,
+	}
+
+	var requeueAfter time.Duration
+
+	switch {
+	case err != nil:
+		condition.Message = err.Error()
+	case !found:
+		condition.Message = "no availability window opens in the next year"
+	case window.Start.After(now):
+		condition.Reason = flarev1alpha1.IntentAvailabilityReasonWindowClosed
+		condition.Message = fmt.Sprintf("workload is scheduled for the window opening at %s", window.Start.UTC().Format(time.RFC3339))
+
+		requeueAfter = window.Start.Sub(now)
+	default:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = flarev1alpha1.IntentAvailabilityReasonWindowOpen
+		condition.Message = fmt.Sprintf("workload is running in the window closing at %s", window.End.UTC().Format(time.RFC3339))
+
+		requeueAfter = window.End.Sub(now)
+	}
+
+	var nextWindow *flarev1alpha1.IntentWindow
+	if found {
+		nextWindow = &flarev1alpha1.IntentWindow{
+			Start: metav1.NewTime(window.Start),
+			End:   metav1.NewTime(window.End),
+		}
+	}
+
+	return requeueAfter, UpdateStatus(ctx, i.Client, intent, func(status *flarev1alpha1.IntentStatus) {
+		meta.SetStatusCondition(&status.Conditions, condition)
+		status.NextWindow = nextWindow
+	})
+}
+
+// outsideAvailability returns true when the workload must be held, or suspended, outside its availability windows.
+func outsideAvailability(intent *flarev1alpha1.Intent) bool {
+	return availability.Outside(intent.Status.Conditions)
+}
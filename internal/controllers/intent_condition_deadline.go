@@ -0,0 +1,93 @@
+// Copyright 2025 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	flarev1alpha1 "github.com/clastix/flare-internal/api/v1alpha1"
+)
+
+// HandleDeadline admits the batch Intent upon its deadline: before the Job is first created, the deadline must be met
+// within the Job timeout, while a running Job is cancelled once the deadline has passed.
+// The admission is granted once for the deadline, the Job being recreated upon the workload changes, or a migration.
+// It returns the time left before the deadline, and false when the Intent has not been admitted.
+func (i *IntentReconciler) HandleDeadline(ctx context.Context, intent *flarev1alpha1.Intent) (time.Duration, bool, error) {
+	deadline := intent.Spec.Constraints.Deadline
+
+	if intent.Spec.Workload.Type != flarev1alpha1.IntentWorkloadTypeBatch || deadline.IsZero() {
+		if meta.FindStatusCondition(intent.Status.Conditions, flarev1alpha1.IntentStatusTypeDeadline) == nil && intent.Status.AdmittedDeadline == nil {
+			return 0, true, nil
+		}
+
+		return 0, true, UpdateStatus(ctx, i.Client, intent, func(status *flarev1alpha1.IntentStatus) {
+			meta.RemoveStatusCondition(&status.Conditions, flarev1alpha1.IntentStatusTypeDeadline)
+			status.AdmittedDeadline = nil
+		})
+	}
+	// A finished Job is not subject to the deadline anymore.
+	if workload := meta.FindStatusCondition(intent.Status.Conditions, flarev1alpha1.IntentStatusTypeWorkload); workload != nil {
+		if workload.Reason == flarev1alpha1.IntentWorkloadReasonSucceeded || workload.Reason == flarev1alpha1.IntentWorkloadReasonFailed {
+			return 0, true, nil
+		}
+	}
+
+	var job batchv1.Job
+
+	jobExists := true
+
+	if err := i.Client.Get(ctx, types.NamespacedName{Name: intent.Namespace, Namespace: intent.Namespace}, &job); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return 0, false, errors.Wrap(err, "cannot retrieve Job")
+		}
+
+		jobExists = false
+	}
+
+	now := time.Now()
+	timeout := intent.Spec.Workload.Batch.Timeout.Duration
+
+	condition := metav1.Condition{
+		Type:               flarev1alpha1.IntentStatusTypeDeadline,
+		ObservedGeneration: intent.Generation,
+		Status:             metav1.ConditionFalse,
+		Reason:             flarev1alpha1.IntentDeadlineReasonExceeded,
+	}
+
+	admitted := jobExists || (intent.Status.AdmittedDeadline != nil && intent.Status.AdmittedDeadline.Equal(&deadline))
+
+	switch {
+	case !now.Before(deadline.Time):
+		condition.Message = fmt.Sprintf("deadline %s has passed", deadline.UTC().Format(time.RFC3339))
+	case !admitted && now.Add(timeout).After(deadline.Time):
+		condition.Message = fmt.Sprintf("deadline %s cannot be met given the %s Job timeout", deadline.UTC().Format(time.RFC3339), timeout)
+	default:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = flarev1alpha1.IntentDeadlineReasonScheduled
+		condition.Message = fmt.Sprintf("workload must complete by %s", deadline.UTC().Format(time.RFC3339))
+
+		return deadline.Sub(now), true, UpdateStatus(ctx, i.Client, intent, func(status *flarev1alpha1.IntentStatus) {
+			meta.SetStatusCondition(&status.Conditions, condition)
+			status.AdmittedDeadline = deadline.DeepCopy()
+		})
+	}
+
+	if jobExists {
+		if err := i.Client.Delete(ctx, &job, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !apierrors.IsNotFound(err) {
+			return 0, false, errors.Wrap(err, "cannot cancel Job upon the deadline")
+		}
+	}
+
+	return 0, false, UpdateStatusCondition(ctx, i.Client, intent, condition)
+}
@@ -0,0 +1,370 @@
+// Copyright 2025 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strconv"
+
+	fluidosnodev1alpha1 "github.com/fluidos-project/node/apis/nodecore/v1alpha1"
+	"github.com/fluidos-project/node/pkg/utils/flags"
+	nodeoffloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	flarev1alpha1 "github.com/clastix/flare-internal/api/v1alpha1"
+)
+
+// SolverName returns the name of the Solver for the given allocation generation:
+// the first one is named after the Intent Namespace, the following ones are suffixed with the generation.
+func SolverName(intent *flarev1alpha1.Intent, generation int64) string {
+	if generation <= 0 {
+		return intent.Namespace
+	}
+
+	return intent.Namespace + "-" + strconv.FormatInt(generation, 10)
+}
+
+// specHash returns a stable hash of the JSON representation of the given object.
+func specHash(obj any) string {
+	data, _ := json.Marshal(obj)
+
+	h := fnv.New64a()
+	_, _ = h.Write(data)
+
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+// allocationHash returns the hash of the Intent fields driving the provider selection:
+// a change in the resources, objective, or solving constraints requires a new allocation,
+// while workload changes are rolled out in place.
+func allocationHash(intent *flarev1alpha1.Intent) string {
+	constraints := intent.Spec.Constraints.DeepCopy()
+	// The following constraints are enforced by FLARE on the running workload.
+	constraints.Availability = flarev1alpha1.IntentWorkloadConstraintAvailability{}
+	constraints.Security = flarev1alpha1.IntentWorkloadConstraintSecurity{}
+	constraints.Deadline = metav1.Time{}
+	constraints.MaxTotalCost = 0
+
+	return specHash(struct {
+		Resources   flarev1alpha1.IntentWorkloadResource
+		Objective   flarev1alpha1.IntentObject
+		Constraints *flarev1alpha1.IntentConstraint
+	}{
+		Resources:   intent.Spec.Workload.Resources,
+		Objective:   intent.Spec.Objective,
+		Constraints: constraints,
+	})
+}
+
+// intentAllocations returns all the allocations tracked by the Intent, starting from the running one.
+func intentAllocations(status flarev1alpha1.IntentStatus) []flarev1alpha1.IntentAllocation {
+	allocations := []flarev1alpha1.IntentAllocation{status.Allocation}
+
+	if status.Migration != nil {
+		allocations = append(allocations, *status.Migration)
+	}
+
+	return append(allocations, status.Retiring...)
+}
+
+// nextGeneration returns the first Solver generation not used by any of the Intent allocations.
+func nextGeneration(status flarev1alpha1.IntentStatus) int64 {
+	var generation int64
+
+	for _, allocation := range intentAllocations(status) {
+		generation = max(generation, allocation.Generation)
+	}
+
+	return generation + 1
+}
+
+// HandleMigration moves the workload to a new provider when the resources or constraints of the Intent change:
+// a new Solver generation is created, its provider cluster is added to the NamespaceOffloading,
+// and once Ready the workload is pinned to it, releasing the previous allocation after the rollout.
+// It returns true when no migration is in progress.
+func (i *IntentReconciler) HandleMigration(ctx context.Context, intent *flarev1alpha1.Intent) (bool, error) {
+	hash := allocationHash(intent)
+
+	condition := meta.FindStatusCondition(intent.Status.Conditions, flarev1alpha1.IntentStatusTypeMigration)
+	if condition == nil {
+		condition = &metav1.Condition{Type: flarev1alpha1.IntentStatusTypeMigration}
+	} else {
+		condition = condition.DeepCopy()
+	}
+
+	condition.Status = metav1.ConditionFalse
+
+	switch {
+	case intent.Status.Allocation.Hash == "":
+		// Intents allocated before the tracking of the allocations are adopted as they are.
+		contract, _, _ := i.retrieveContract(ctx, intent, intent.Status.Allocation.Generation)
+
+		return false, UpdateStatus(ctx, i.Client, intent, func(status *flarev1alpha1.IntentStatus) {
+			status.Allocation.Hash = hash
+
+			if contract != nil && status.Allocation.ClusterID == "" {
+				status.Allocation.ClusterID = contract.Spec.PeeringTargetCredentials.ClusterID
+			}
+		})
+	case intent.Status.Migration != nil && intent.Status.Migration.Hash != hash:
+		condition.Reason = "MigrationRestarted"
+		condition.Message = "Resources or constraints have changed during the migration"
+
+		return false, UpdateStatus(ctx, i.Client, intent, func(status *flarev1alpha1.IntentStatus) {
+			meta.SetStatusCondition(&status.Conditions, *condition)
+
+			if status.Migration != nil {
+				status.Retiring = append(status.Retiring, *status.Migration)
+				status.Migration = nil
+				// The queue would retry the discovery of the running allocation.
+				status.Queue = nil
+			}
+		})
+	case len(intent.Status.Retiring) > 0:
+		return false, i.retireAllocations(ctx, intent, condition)
+	case intent.Status.Migration == nil && intent.Status.Allocation.Hash != hash:
+		generation := nextGeneration(intent.Status)
+
+		condition.Reason = "MigrationStarted"
+		condition.Message = fmt.Sprintf("Resources or constraints have changed, allocating Solver generation %d", generation)
+
+		return false, UpdateStatus(ctx, i.Client, intent, func(status *flarev1alpha1.IntentStatus) {
+			meta.SetStatusCondition(&status.Conditions, *condition)
+
+			if status.Migration == nil {
+				status.Migration = &flarev1alpha1.IntentAllocation{Generation: generation, Hash: hash}
+			}
+		})
+	case intent.Status.Migration != nil:
+		return false, i.migrateAllocation(ctx, intent, condition)
+	}
+
+	if meta.FindStatusCondition(intent.Status.Conditions, flarev1alpha1.IntentStatusTypeMigration) != nil {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "MigrationCompleted"
+		condition.Message = ""
+
+		if err := UpdateStatusCondition(ctx, i.Client, intent, *condition); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// migrateAllocation drives the Solver generation being allocated up to its offloading,
+// promoting it as the running allocation once the remote Namespace is Ready.
+func (i *IntentReconciler) migrateAllocation(ctx context.Context, intent *flarev1alpha1.Intent, condition *metav1.Condition) error {
+	if queued(intent) {
+		return i.HandleQueue(ctx, intent)
+	}
+
+	migration := *intent.Status.Migration
+
+	var solver fluidosnodev1alpha1.Solver
+	solver.Name = SolverName(intent, migration.Generation)
+	solver.Namespace = flags.FluidosNamespace
+
+	if err := i.Client.Get(ctx, types.NamespacedName{Name: solver.Name, Namespace: solver.Namespace}, &solver); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrap(err, "cannot retrieve migration Solver")
+		}
+
+		if _, err = i.ensureSolver(ctx, intent, &solver); err != nil {
+			condition.Reason = "SolverCreationFailed"
+			condition.Message = err.Error()
+
+			return UpdateStatusCondition(ctx, i.Client, intent, *condition)
+		}
+
+		condition.Reason = "SolverCreationCompleted"
+		condition.Message = "Solver " + solver.Name + " has been created, waiting for its solving."
+
+		return UpdateStatusCondition(ctx, i.Client, intent, *condition)
+	}
+
+	if discoveryCompleted(&solver) {
+		selection, err := i.selectCandidate(ctx, intent, &solver, migration.Negotiation)
+		if err != nil || selection == nil {
+			return err
+		}
+
+		condition.Reason = selection.reason
+		condition.Message = selection.message
+
+		return UpdateStatus(ctx, i.Client, intent, func(status *flarev1alpha1.IntentStatus) {
+			if status.Migration != nil && status.Migration.Generation == migration.Generation {
+				status.Migration.Negotiation = selection.negotiation
+
+				if selection.candidate != nil {
+					status.Migration.Candidate = selection.candidate
+				}
+
+				i.queueSelection(intent, status, selection, condition)
+			}
+
+			meta.SetStatusCondition(&status.Conditions, *condition)
+		})
+	}
+
+	if solver.Status.SolverPhase.Phase != fluidosnodev1alpha1.PhaseSolved {
+		condition.Reason = "SolverNotSolved"
+		condition.Message = fmt.Sprintf("Solver %s is in phase %q: %s", solver.Name, solver.Status.SolverPhase.Phase, solver.Status.SolverPhase.Message)
+
+		return UpdateStatusCondition(ctx, i.Client, intent, *condition)
+	}
+
+	if migration.ClusterID == "" {
+		contract, reason, message := i.retrieveContract(ctx, intent, migration.Generation)
+		if contract == nil {
+			condition.Reason = reason
+			condition.Message = message
+
+			return UpdateStatusCondition(ctx, i.Client, intent, *condition)
+		}
+
+		condition.Reason = "ContractRetrieved"
+		condition.Message = "Offloading towards " + contract.Spec.PeeringTargetCredentials.ClusterID
+
+		return UpdateStatus(ctx, i.Client, intent, func(status *flarev1alpha1.IntentStatus) {
+			meta.SetStatusCondition(&status.Conditions, *condition)
+
+			if status.Migration != nil && status.Migration.Generation == migration.Generation {
+				status.Migration.ClusterID = contract.Spec.PeeringTargetCredentials.ClusterID
+			}
+		})
+	}
+
+	_, nsOffloading, err := i.ensureNamespaceOffloading(ctx, intent, offloadingClusterIDs(intentAllocations(intent.Status)...))
+	if err != nil {
+		condition.Reason = "NamespaceOffloadingUpdateFailed"
+		condition.Message = err.Error()
+
+		return UpdateStatusCondition(ctx, i.Client, intent, *condition)
+	}
+
+	if !remoteNamespaceReady(nsOffloading, migration.ClusterID) {
+		condition.Reason = "NamespaceOffloadingInProgress"
+		condition.Message = "Waiting for the remote Namespace on " + migration.ClusterID
+
+		return UpdateStatusCondition(ctx, i.Client, intent, *condition)
+	}
+
+	condition.Reason = "WorkloadRollout"
+	condition.Message = "Moving the workload to " + migration.ClusterID
+
+	return UpdateStatus(ctx, i.Client, intent, func(status *flarev1alpha1.IntentStatus) {
+		meta.SetStatusCondition(&status.Conditions, *condition)
+
+		if status.Migration == nil || status.Migration.Generation != migration.Generation {
+			return
+		}
+
+		status.Retiring = append(status.Retiring, status.Allocation)
+		status.Allocation = *status.Migration
+		status.Migration = nil
+	})
+}
+
+// retireAllocations releases the allocations replaced by a migration once the workload has been rolled out.
+func (i *IntentReconciler) retireAllocations(ctx context.Context, intent *flarev1alpha1.Intent, condition *metav1.Condition) error {
+	rolledOut, err := i.workloadRolledOut(ctx, intent)
+	if err != nil {
+		return errors.Wrap(err, "cannot check workload rollout")
+	}
+
+	if !rolledOut {
+		condition.Reason = "WorkloadRollout"
+		condition.Message = "Waiting for the workload rollout on " + intent.Status.Allocation.ClusterID
+
+		return UpdateStatusCondition(ctx, i.Client, intent, *condition)
+	}
+
+	if _, _, err = i.ensureNamespaceOffloading(ctx, intent, offloadingClusterIDs(intent.Status.Allocation)); err != nil {
+		condition.Reason = "NamespaceOffloadingUpdateFailed"
+		condition.Message = err.Error()
+
+		return UpdateStatusCondition(ctx, i.Client, intent, *condition)
+	}
+
+	retiring := intent.Status.Retiring[0]
+
+	gone, err := i.releaseAllocation(ctx, intent, retiring, condition)
+	if err != nil {
+		return err
+	}
+
+	if !gone {
+		return UpdateStatusCondition(ctx, i.Client, intent, *condition)
+	}
+
+	return UpdateStatus(ctx, i.Client, intent, func(status *flarev1alpha1.IntentStatus) {
+		status.Retiring = slices.DeleteFunc(status.Retiring, func(allocation flarev1alpha1.IntentAllocation) bool {
+			return allocation.Generation == retiring.Generation
+		})
+	})
+}
+
+// remoteNamespaceReady returns true when the NamespaceOffloading has processed its latest
+// specification, and the remote Namespace on the given cluster is Ready.
+func remoteNamespaceReady(nsOffloading *nodeoffloadingv1beta1.NamespaceOffloading, clusterID string) bool {
+	if nsOffloading.Status.ObservedGeneration < nsOffloading.Generation {
+		return false
+	}
+
+	for _, condition := range nsOffloading.Status.RemoteNamespacesConditions[clusterID] {
+		if condition.Type == nodeoffloadingv1beta1.NamespaceReady && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+
+	return false
+}
+
+// workloadRolledOut returns true when the workload has been updated to the latest pod template.
+func (i *IntentReconciler) workloadRolledOut(ctx context.Context, intent *flarev1alpha1.Intent) (bool, error) {
+	key := types.NamespacedName{Name: intent.Namespace, Namespace: intent.Namespace}
+
+	switch intent.Spec.Workload.Type {
+	case flarev1alpha1.IntentWorkloadTypeService:
+		var deployment appsv1.Deployment
+		if err := i.Client.Get(ctx, key, &deployment); err != nil {
+			return apierrors.IsNotFound(err), client.IgnoreNotFound(err)
+		}
+
+		replicas := ptr.Deref(deployment.Spec.Replicas, 1)
+
+		return deployment.Status.ObservedGeneration >= deployment.Generation &&
+			deployment.Status.UpdatedReplicas == replicas &&
+			deployment.Status.Replicas == replicas &&
+			deployment.Status.AvailableReplicas == replicas, nil
+	case flarev1alpha1.IntentWorkloadTypeBatch:
+		var job batchv1.Job
+		if err := i.Client.Get(ctx, key, &job); err != nil {
+			return apierrors.IsNotFound(err), client.IgnoreNotFound(err)
+		}
+
+		immutable, err := i.jobImmutableSpec(intent)
+		if err != nil {
+			return false, err
+		}
+
+		return job.Annotations[jobSpecHashAnnotation] == specHash(immutable), nil
+	}
+
+	return true, nil
+}
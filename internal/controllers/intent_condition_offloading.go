@@ -17,6 +17,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	flarev1alpha1 "github.com/clastix/flare-internal/api/v1alpha1"
@@ -84,36 +85,76 @@ func (i *IntentReconciler) HandleNamespaceOffloadingPhase(ctx context.Context, i
 func (i *IntentReconciler) CreateNamespaceOffloading(ctx context.Context, intent *flarev1alpha1.Intent) error {
 	condition := meta.FindStatusCondition(intent.Status.Conditions, flarev1alpha1.IntentStatusTypeOffloading).DeepCopy()
 
-	var reservation fluidosreservationv1alpha1.Reservation
-	if err := i.Client.Get(ctx, types.NamespacedName{Name: "reservation-" + intent.Namespace, Namespace: flags.FluidosNamespace}, &reservation); err != nil {
-		condition.Reason = "ReservationNotFound"
-		condition.Message = err.Error()
+	contract, reason, message := i.retrieveContract(ctx, intent, intent.Status.Allocation.Generation)
+	if contract == nil {
+		condition.Reason = reason
+		condition.Message = message
 
 		return UpdateStatusCondition(ctx, i.Client, intent, *condition)
 	}
 
-	if reservation.Status.Contract.Name == "" {
-		condition.Reason = "MissingContractReference"
-		condition.Message = "Missing Name or Namespace in Contract status"
+	clusterID := contract.Spec.PeeringTargetCredentials.ClusterID
 
-		return UpdateStatusCondition(ctx, i.Client, intent, *condition)
+	allocation := intent.Status.Allocation
+	allocation.ClusterID = clusterID
+
+	allocations := intentAllocations(intent.Status)
+	allocations[0] = allocation
+
+	or, nsOffloading, err := i.ensureNamespaceOffloading(ctx, intent, offloadingClusterIDs(allocations...))
+	switch {
+	case err != nil:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "NamespaceOffloadingCreationFailed"
+		condition.Message = err.Error()
+	case or == controllerutil.OperationResultCreated, or == controllerutil.OperationResultNone:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "NamespaceOffloadingCreationCompleted"
+		condition.Message = "NamespaceOffloading has been created, waiting for its solving."
+	case or == controllerutil.OperationResultUpdated:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "NamespaceOffloadingCreationCompleted"
+		condition.Message = "NamespaceOffloading has been updated, waiting for its solving."
+	}
+
+	condition.ObservedGeneration = nsOffloading.Generation
+
+	return UpdateStatus(ctx, i.Client, intent, func(status *flarev1alpha1.IntentStatus) {
+		meta.SetStatusCondition(&status.Conditions, *condition)
+
+		if err == nil {
+			status.Allocation.ClusterID = clusterID
+		}
+	})
+}
+
+// retrieveContract returns the Contract bought by the Solver of the given generation:
+// when it's not available, the returned reason and message describe the missing step.
+func (i *IntentReconciler) retrieveContract(ctx context.Context, intent *flarev1alpha1.Intent, generation int64) (*fluidosreservationv1alpha1.Contract, string, string) {
+	var reservation fluidosreservationv1alpha1.Reservation
+	if err := i.Client.Get(ctx, types.NamespacedName{Name: "reservation-" + SolverName(intent, generation), Namespace: flags.FluidosNamespace}, &reservation); err != nil {
+		return nil, "ReservationNotFound", err.Error()
+	}
+
+	if reservation.Status.Contract.Name == "" {
+		return nil, "MissingContractReference", "Missing Name or Namespace in Contract status"
 	}
 
 	var contract fluidosreservationv1alpha1.Contract
 	if err := i.Client.Get(ctx, types.NamespacedName{Name: reservation.Status.Contract.Name, Namespace: reservation.Status.Contract.Namespace}, &contract); err != nil {
-		condition.Reason = "ContractNotFound"
-		condition.Message = err.Error()
-
-		return UpdateStatusCondition(ctx, i.Client, intent, *condition)
+		return nil, "ContractNotFound", err.Error()
 	}
 
 	if contract.Spec.PeeringTargetCredentials.ClusterID == "" {
-		condition.Reason = "MissingPeeringTargetCredentials"
-		condition.Message = "LiqoID field is empty"
-
-		return UpdateStatusCondition(ctx, i.Client, intent, *condition)
+		return nil, "MissingPeeringTargetCredentials", "LiqoID field is empty"
 	}
 
+	return &contract, "", ""
+}
+
+// ensureNamespaceOffloading creates or updates the NamespaceOffloading of the Intent Namespace,
+// selecting the virtual nodes of the given provider clusters.
+func (i *IntentReconciler) ensureNamespaceOffloading(ctx context.Context, intent *flarev1alpha1.Intent, clusterIDs []string) (controllerutil.OperationResult, *nodeoffloadingv1beta1.NamespaceOffloading, error) {
 	var nsOffloading nodeoffloadingv1beta1.NamespaceOffloading
 	nsOffloading.Name = liqoconsts.DefaultNamespaceOffloadingName
 	nsOffloading.Namespace = intent.Namespace
@@ -127,9 +168,7 @@ func (i *IntentReconciler) CreateNamespaceOffloading(ctx context.Context, intent
 					{
 						Key:      fluidosconsts.LiqoRemoteClusterIDLabel,
 						Operator: corev1.NodeSelectorOpIn,
-						Values: []string{
-							contract.Spec.PeeringTargetCredentials.ClusterID,
-						},
+						Values:   clusterIDs,
 					},
 				},
 			},
@@ -137,22 +176,19 @@ func (i *IntentReconciler) CreateNamespaceOffloading(ctx context.Context, intent
 
 		return nil
 	})
-	switch {
-	case err != nil:
-		condition.Status = metav1.ConditionFalse
-		condition.Reason = "NamespaceOffloadingCreationFailed"
-		condition.Message = err.Error()
-	case or == controllerutil.OperationResultCreated, or == controllerutil.OperationResultNone:
-		condition.Status = metav1.ConditionFalse
-		condition.Reason = "NamespaceOffloadingCreationCompleted"
-		condition.Message = "NamespaceOffloading has been created, waiting for its solving."
-	case or == controllerutil.OperationResultUpdated:
-		condition.Status = metav1.ConditionFalse
-		condition.Reason = "NamespaceOffloadingCreationCompleted"
-		condition.Message = "NamespaceOffloading has been updated, waiting for its solving."
-	}
 
-	condition.ObservedGeneration = nsOffloading.Generation
+	return or, &nsOffloading, err
+}
 
-	return UpdateStatusCondition(ctx, i.Client, intent, *condition)
+// offloadingClusterIDs returns the sorted and unique provider clusters of the given allocations.
+func offloadingClusterIDs(allocations ...flarev1alpha1.IntentAllocation) []string {
+	clusterIDs := sets.New[string]()
+
+	for _, allocation := range allocations {
+		if allocation.ClusterID != "" {
+			clusterIDs.Insert(allocation.ClusterID)
+		}
+	}
+
+	return sets.List(clusterIDs)
 }
@@ -5,6 +5,8 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	fluidosnodev1alpha1 "github.com/fluidos-project/node/apis/nodecore/v1alpha1"
 	"github.com/fluidos-project/node/pkg/utils/flags"
@@ -21,11 +23,18 @@ import (
 	flarev1alpha1 "github.com/clastix/flare-internal/api/v1alpha1"
 )
 
-//+kubebuilder:rbac:groups=nodecore.fluidos.eu,resources=solvers,verbs=get;list;watch;create
+//+kubebuilder:rbac:groups=nodecore.fluidos.eu,resources=solvers,verbs=get;list;watch;create;update
 
 func (i *IntentReconciler) HandleSolverPhase(ctx context.Context, intent *flarev1alpha1.Intent) error {
+	// The queue retries the discovery of the migrating allocation, if any, handled along with the migration.
+	migrating := intent.Status.Migration != nil
+
+	if queued(intent) && !migrating {
+		return i.HandleQueue(ctx, intent)
+	}
+
 	var solver fluidosnodev1alpha1.Solver
-	if err := i.Client.Get(ctx, types.NamespacedName{Name: intent.Namespace, Namespace: flags.FluidosNamespace}, &solver); err != nil {
+	if err := i.Client.Get(ctx, types.NamespacedName{Name: SolverName(intent, intent.Status.Allocation.Generation), Namespace: flags.FluidosNamespace}, &solver); err != nil {
 		if apierrors.IsNotFound(err) {
 			return i.CreateSolver(ctx, intent)
 		}
@@ -33,7 +42,50 @@ func (i *IntentReconciler) HandleSolverPhase(ctx context.Context, intent *flarev
 		return errors.Wrap(err, "cannot handle Solver")
 	}
 
+	// The Solver deleted for a new discovery of the queued Intent is still being removed.
+	if queue := intent.Status.Queue; solver.DeletionTimestamp != nil || (!migrating && queue != nil && queue.LastAttemptTime != nil && solver.CreationTimestamp.Before(queue.LastAttemptTime)) {
+		return nil
+	}
+
 	condition := meta.FindStatusCondition(intent.Status.Conditions, flarev1alpha1.IntentStatusTypeSolver).DeepCopy()
+	condition.ObservedGeneration = solver.Generation
+
+	if discoveryCompleted(&solver) {
+		selection, err := i.selectCandidate(ctx, intent, &solver, intent.Status.Allocation.Negotiation)
+		if err != nil || selection == nil {
+			return err
+		}
+
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = selection.reason
+		condition.Message = selection.message
+
+		return UpdateStatus(ctx, i.Client, intent, func(status *flarev1alpha1.IntentStatus) {
+			if SolverName(intent, status.Allocation.Generation) == solver.Name {
+				status.Allocation.Negotiation = selection.negotiation
+
+				if selection.candidate != nil {
+					status.Allocation.Candidate = selection.candidate
+				}
+
+				i.queueSelection(intent, status, selection, condition)
+			}
+
+			meta.SetStatusCondition(&status.Conditions, *condition)
+		})
+	}
+
+	if phase := solver.Status.SolverPhase.Phase; condition.Status != metav1.ConditionTrue && (phase == fluidosnodev1alpha1.PhaseFailed || phase == fluidosnodev1alpha1.PhaseTimeout) {
+		return UpdateStatus(ctx, i.Client, intent, func(status *flarev1alpha1.IntentStatus) {
+			next := i.enqueue(status, 0)
+
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = flarev1alpha1.IntentSolverReasonQueued
+			condition.Message = fmt.Sprintf("Solver %s is in phase %q: %s, queued for a new discovery at %s", solver.Name, phase, solver.Status.SolverPhase.Message, next.UTC().Format(time.RFC3339))
+
+			meta.SetStatusCondition(&status.Conditions, *condition)
+		})
+	}
 
 	switch solver.Status.SolverPhase.Phase {
 	case fluidosnodev1alpha1.PhaseSolved:
@@ -65,24 +117,60 @@ func (i *IntentReconciler) HandleSolverPhase(ctx context.Context, intent *flarev
 		condition.Reason = "SolverInactive"
 	}
 
-	condition.ObservedGeneration = solver.Generation
 	condition.Message = solver.Status.SolverPhase.Message
 
 	return UpdateStatusCondition(ctx, i.Client, intent, *condition)
 }
 
+// discoveryCompleted returns true when the Solver has discovered the PeeringCandidates,
+// waiting for FLARE to select the one to be reserved.
+func discoveryCompleted(solver *fluidosnodev1alpha1.Solver) bool {
+	return solver.Status.SolverPhase.Phase == fluidosnodev1alpha1.PhaseSolved && !solver.Spec.ReserveAndBuy
+}
+
 func (i *IntentReconciler) CreateSolver(ctx context.Context, intent *flarev1alpha1.Intent) error {
 	condition := meta.FindStatusCondition(intent.Status.Conditions, flarev1alpha1.IntentStatusTypeSolver).DeepCopy()
 
 	var solver fluidosnodev1alpha1.Solver
-	solver.Name = intent.Namespace
+	solver.Name = SolverName(intent, intent.Status.Allocation.Generation)
 	solver.Namespace = flags.FluidosNamespace
 
-	res, err := controllerutil.CreateOrUpdate(ctx, i.Client, &solver, func() error {
+	hash := allocationHash(intent)
+
+	res, err := i.ensureSolver(ctx, intent, &solver)
+	switch {
+	case err != nil:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "SolverCreationFailed"
+		condition.Message = err.Error()
+	case res == controllerutil.OperationResultCreated, res == controllerutil.OperationResultNone:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "SolverCreationCompleted"
+		condition.Message = "Solver has been created, waiting for its solving."
+	case res == controllerutil.OperationResultUpdated:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "SolverCreationCompleted"
+		condition.Message = "Solver has been updated, waiting for its solving."
+	}
+
+	condition.ObservedGeneration = solver.Generation
+
+	return UpdateStatus(ctx, i.Client, intent, func(status *flarev1alpha1.IntentStatus) {
+		meta.SetStatusCondition(&status.Conditions, *condition)
+
+		if err == nil && status.Allocation.Hash == "" {
+			status.Allocation.Hash = hash
+		}
+	})
+}
+
+// ensureSolver creates or updates the given Solver according to the Intent resources requirements.
+// The Solver is created for the discovery only: the reservation and peering are enabled
+// once the PeeringCandidate satisfying the Intent constraints has been selected.
+func (i *IntentReconciler) ensureSolver(ctx context.Context, intent *flarev1alpha1.Intent, solver *fluidosnodev1alpha1.Solver) (controllerutil.OperationResult, error) {
+	return controllerutil.CreateOrUpdate(ctx, i.Client, solver, func() error {
 		solver.Spec.IntentID = intent.Namespace
 		solver.Spec.FindCandidate = true
-		solver.Spec.ReserveAndBuy = true
-		solver.Spec.EstablishPeering = true
 
 		sliceSelector := fluidosnodev1alpha1.K8SliceSelector{
 			GPUFilters: make([]fluidosnodev1alpha1.GPUFieldSelector, 0),
@@ -325,7 +413,7 @@ func (i *IntentReconciler) CreateSolver(ctx context.Context, intent *flarev1alph
 			}
 
 			sliceSelector.MemoryFilter.Data.Raw, _ = json.Marshal(fluidosnodev1alpha1.ResourceRangeSelector{
-				Min: &intent.Spec.Workload.Resources.Memory,
+				Min: ptr.To(negotiableMinimum(intent, intent.Spec.Workload.Resources.Memory)),
 			})
 		}
 
@@ -336,7 +424,7 @@ func (i *IntentReconciler) CreateSolver(ctx context.Context, intent *flarev1alph
 			}
 
 			sliceSelector.CPUFilter.Data.Raw, _ = json.Marshal(fluidosnodev1alpha1.ResourceRangeSelector{
-				Min: &intent.Spec.Workload.Resources.CPU,
+				Min: ptr.To(negotiableMinimum(intent, intent.Spec.Workload.Resources.CPU)),
 			})
 		}
 
@@ -349,24 +437,6 @@ func (i *IntentReconciler) CreateSolver(ctx context.Context, intent *flarev1alph
 
 		return nil
 	})
-	switch {
-	case err != nil:
-		condition.Status = metav1.ConditionFalse
-		condition.Reason = "SolverCreationFailed"
-		condition.Message = err.Error()
-	case res == controllerutil.OperationResultCreated, res == controllerutil.OperationResultNone:
-		condition.Status = metav1.ConditionFalse
-		condition.Reason = "SolverCreationCompleted"
-		condition.Message = "Solver has been created, waiting for its solving."
-	case res == controllerutil.OperationResultUpdated:
-		condition.Status = metav1.ConditionFalse
-		condition.Reason = "SolverCreationCompleted"
-		condition.Message = "Solver has been updated, waiting for its solving."
-	}
-
-	condition.ObservedGeneration = solver.Generation
-
-	return UpdateStatusCondition(ctx, i.Client, intent, *condition)
 }
 
 func (i *IntentReconciler) TrackSolverUnknown(ctx context.Context, intent *flarev1alpha1.Intent) error {
@@ -0,0 +1,262 @@
+// Copyright 2025 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"slices"
+
+	fluidosnodev1alpha1 "github.com/fluidos-project/node/apis/nodecore/v1alpha1"
+	fluidosreservationv1alpha1 "github.com/fluidos-project/node/apis/reservation/v1alpha1"
+	fluidosconsts "github.com/fluidos-project/node/pkg/utils/consts"
+	"github.com/fluidos-project/node/pkg/utils/flags"
+	liqoauthenticationv1beta1 "github.com/liqotech/liqo/apis/authentication/v1beta1"
+	nodeoffloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
+	liqoconsts "github.com/liqotech/liqo/pkg/consts"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	flarev1alpha1 "github.com/clastix/flare-internal/api/v1alpha1"
+)
+
+//+kubebuilder:rbac:groups=flare.clastix.io,resources=intents/finalizers,verbs=update
+
+// EnsureCleanupFinalizer adds the cleanup finalizer to the Intent, returning true when the object has been updated.
+func (i *IntentReconciler) EnsureCleanupFinalizer(ctx context.Context, intent *flarev1alpha1.Intent) (bool, error) {
+	if controllerutil.ContainsFinalizer(intent, flarev1alpha1.IntentCleanupFinalizer) {
+		return false, nil
+	}
+
+	controllerutil.AddFinalizer(intent, flarev1alpha1.IntentCleanupFinalizer)
+
+	return true, i.Client.Update(ctx, intent)
+}
+
+//+kubebuilder:rbac:groups=nodecore.fluidos.eu,resources=solvers,verbs=delete
+//+kubebuilder:rbac:groups=reservation.fluidos.eu,resources=reservations;contracts,verbs=delete
+//+kubebuilder:rbac:groups=offloading.liqo.io,resources=namespaceoffloadings,verbs=delete
+//+kubebuilder:rbac:groups=offloading.liqo.io,resources=virtualnodes,verbs=get;list;watch;delete
+//+kubebuilder:rbac:groups=authentication.liqo.io,resources=resourceslices,verbs=get;list;watch;delete
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=delete
+
+// HandleTermination tears down the resources allocated for the Intent in the following order:
+// the NamespaceOffloading is removed, each allocation is released, and finally the Namespace.
+// It returns true once all the resources are gone and the cleanup finalizer has been removed.
+func (i *IntentReconciler) HandleTermination(ctx context.Context, intent *flarev1alpha1.Intent) (bool, error) {
+	if !controllerutil.ContainsFinalizer(intent, flarev1alpha1.IntentCleanupFinalizer) {
+		return true, nil
+	}
+
+	condition := meta.FindStatusCondition(intent.Status.Conditions, flarev1alpha1.IntentStatusTypeTerminating)
+	if condition == nil {
+		condition = &metav1.Condition{Type: flarev1alpha1.IntentStatusTypeTerminating}
+	} else {
+		condition = condition.DeepCopy()
+	}
+
+	condition.Status = metav1.ConditionTrue
+
+	released, err := i.ReleaseAllocations(ctx, intent, condition)
+	if err != nil {
+		return false, err
+	}
+
+	if !released {
+		return false, UpdateStatusCondition(ctx, i.Client, intent, *condition)
+	}
+
+	var ns corev1.Namespace
+	ns.Name = intent.Namespace
+
+	if err := i.Client.Delete(ctx, &ns); err != nil && !apierrors.IsNotFound(err) {
+		return false, errors.Wrap(err, "cannot delete Namespace")
+	}
+
+	controllerutil.RemoveFinalizer(intent, flarev1alpha1.IntentCleanupFinalizer)
+
+	if err := i.Client.Update(ctx, intent); err != nil {
+		return false, errors.Wrap(err, "cannot remove cleanup finalizer")
+	}
+
+	return true, nil
+}
+
+// ReleaseAllocations removes the NamespaceOffloading, and releases each allocation of the Intent.
+// It returns true once all the resources are gone, otherwise the condition reports the pending step.
+func (i *IntentReconciler) ReleaseAllocations(ctx context.Context, intent *flarev1alpha1.Intent, condition *metav1.Condition) (bool, error) {
+	var nsOffloading nodeoffloadingv1beta1.NamespaceOffloading
+	nsOffloading.Name = liqoconsts.DefaultNamespaceOffloadingName
+	nsOffloading.Namespace = intent.Namespace
+
+	gone, err := i.deleteAndCheck(ctx, &nsOffloading)
+	if err != nil {
+		return false, errors.Wrap(err, "cannot delete NamespaceOffloading")
+	}
+
+	if !gone {
+		condition.Reason = "NamespaceOffloadingDeletion"
+		condition.Message = "Waiting for the NamespaceOffloading removal"
+
+		return false, nil
+	}
+
+	for _, allocation := range intentAllocations(intent.Status) {
+		gone, err = i.releaseAllocation(ctx, intent, allocation, condition)
+		if err != nil {
+			return false, err
+		}
+
+		if !gone {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// releaseAllocation disables the peering with the provider of the given allocation if no other Contract
+// is targeting it, and deletes the FLUIDOS Contract, Reservation, and Solver.
+// It returns true once all the resources are gone, otherwise the condition reports the pending step.
+func (i *IntentReconciler) releaseAllocation(ctx context.Context, intent *flarev1alpha1.Intent, allocation flarev1alpha1.IntentAllocation, condition *metav1.Condition) (bool, error) {
+	solverName := SolverName(intent, allocation.Generation)
+
+	var reservation fluidosreservationv1alpha1.Reservation
+	reservationErr := i.Client.Get(ctx, types.NamespacedName{Name: "reservation-" + solverName, Namespace: flags.FluidosNamespace}, &reservation)
+	if reservationErr != nil && !apierrors.IsNotFound(reservationErr) {
+		return false, errors.Wrap(reservationErr, "cannot retrieve Reservation")
+	}
+
+	var contract *fluidosreservationv1alpha1.Contract
+	if reservationErr == nil && reservation.Status.Contract.Name != "" {
+		contract = &fluidosreservationv1alpha1.Contract{}
+		if err := i.Client.Get(ctx, types.NamespacedName{Name: reservation.Status.Contract.Name, Namespace: reservation.Status.Contract.Namespace}, contract); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return false, errors.Wrap(err, "cannot retrieve Contract")
+			}
+
+			contract = nil
+		}
+	}
+
+	if contract != nil && contract.Spec.PeeringTargetCredentials.ClusterID != "" {
+		gone, err := i.unpeer(ctx, contract)
+		if err != nil {
+			return false, err
+		}
+
+		if !gone {
+			condition.Reason = "Unpeering"
+			condition.Message = "Waiting for the peering with " + contract.Spec.PeeringTargetCredentials.ClusterID + " to be disabled"
+
+			return false, nil
+		}
+	}
+
+	var solver fluidosnodev1alpha1.Solver
+	solver.Name = solverName
+	solver.Namespace = flags.FluidosNamespace
+
+	objects := []client.Object{&solver}
+	if reservationErr == nil {
+		objects = append(objects, &reservation)
+	}
+
+	if contract != nil {
+		objects = append(objects, contract)
+	}
+
+	for _, obj := range objects {
+		gone, err := i.deleteAndCheck(ctx, obj)
+		if err != nil {
+			return false, errors.Wrap(err, "cannot delete FLUIDOS resources")
+		}
+
+		if !gone {
+			condition.Reason = "FluidosResourcesDeletion"
+			condition.Message = "Waiting for the Solver, Reservation, and Contract removal of " + solverName
+
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// unpeer disables the offloading towards the provider of the given Contract,
+// unless it's still required by other Contracts.
+func (i *IntentReconciler) unpeer(ctx context.Context, contract *fluidosreservationv1alpha1.Contract) (bool, error) {
+	clusterID := contract.Spec.PeeringTargetCredentials.ClusterID
+
+	var contractList fluidosreservationv1alpha1.ContractList
+	if err := i.Client.List(ctx, &contractList); err != nil {
+		return false, errors.Wrap(err, "cannot list Contracts")
+	}
+
+	inUse := slices.ContainsFunc(contractList.Items, func(item fluidosreservationv1alpha1.Contract) bool {
+		return item.UID != contract.UID && item.DeletionTimestamp == nil && item.Spec.PeeringTargetCredentials.ClusterID == clusterID
+	})
+	if inUse {
+		return true, nil
+	}
+
+	var resourceSliceList liqoauthenticationv1beta1.ResourceSliceList
+	if err := i.Client.List(ctx, &resourceSliceList, client.MatchingLabels{
+		liqoconsts.ReplicationRequestedLabel:   liqoconsts.ReplicationRequestedLabelValue,
+		liqoconsts.ReplicationDestinationLabel: clusterID,
+	}); err != nil {
+		return false, errors.Wrap(err, "cannot list ResourceSlices")
+	}
+
+	var virtualNodeList nodeoffloadingv1beta1.VirtualNodeList
+	if err := i.Client.List(ctx, &virtualNodeList, client.MatchingLabels{fluidosconsts.LiqoRemoteClusterIDLabel: clusterID}); err != nil {
+		return false, errors.Wrap(err, "cannot list VirtualNodes")
+	}
+
+	gone := true
+
+	for _, resourceSlice := range resourceSliceList.Items {
+		if err := i.Client.Delete(ctx, &resourceSlice); err != nil && !apierrors.IsNotFound(err) {
+			return false, errors.Wrap(err, "cannot delete ResourceSlice")
+		}
+
+		gone = false
+	}
+
+	for _, virtualNode := range virtualNodeList.Items {
+		if err := i.Client.Delete(ctx, &virtualNode); err != nil && !apierrors.IsNotFound(err) {
+			return false, errors.Wrap(err, "cannot delete VirtualNode")
+		}
+
+		gone = false
+	}
+
+	return gone, nil
+}
+
+// deleteAndCheck deletes the given object, returning true once it's no more present.
+func (i *IntentReconciler) deleteAndCheck(ctx context.Context, obj client.Object) (bool, error) {
+	if err := i.Client.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+
+		return false, err
+	}
+
+	if obj.GetDeletionTimestamp() != nil {
+		return false, nil
+	}
+
+	if err := i.Client.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+		return false, err
+	}
+
+	return false, nil
+}
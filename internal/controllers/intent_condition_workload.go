@@ -0,0 +1,140 @@
+// Copyright 2025 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	flarev1alpha1 "github.com/clastix/flare-internal/api/v1alpha1"
+)
+
+// HandleWorkloadPhase reports the lifecycle of the Intent workload according to the status of its Job or Deployment.
+func (i *IntentReconciler) HandleWorkloadPhase(ctx context.Context, intent *flarev1alpha1.Intent) error {
+	condition := meta.FindStatusCondition(intent.Status.Conditions, flarev1alpha1.IntentStatusTypeWorkload)
+	if condition == nil {
+		condition = &metav1.Condition{Type: flarev1alpha1.IntentStatusTypeWorkload}
+	} else {
+		condition = condition.DeepCopy()
+	}
+
+	key := types.NamespacedName{Name: intent.Namespace, Namespace: intent.Namespace}
+
+	switch intent.Spec.Workload.Type {
+	case flarev1alpha1.IntentWorkloadTypeBatch:
+		var job batchv1.Job
+		if err := i.Client.Get(ctx, key, &job); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return errors.Wrap(err, "cannot retrieve Job")
+			}
+
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = flarev1alpha1.IntentWorkloadReasonPending
+			condition.Message = "Job has not been created yet"
+
+			break
+		}
+
+		jobWorkloadCondition(&job, condition)
+	case flarev1alpha1.IntentWorkloadTypeService:
+		var deployment appsv1.Deployment
+		if err := i.Client.Get(ctx, key, &deployment); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return errors.Wrap(err, "cannot retrieve Deployment")
+			}
+
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = flarev1alpha1.IntentWorkloadReasonPending
+			condition.Message = "Deployment has not been created yet"
+
+			break
+		}
+
+		deploymentWorkloadCondition(&deployment, condition)
+	}
+
+	return UpdateStatusCondition(ctx, i.Client, intent, *condition)
+}
+
+func jobWorkloadCondition(job *batchv1.Job, condition *metav1.Condition) {
+	pods := fmt.Sprintf("%d active, %d ready, %d succeeded, %d failed pods", job.Status.Active, ptr.Deref(job.Status.Ready, 0), job.Status.Succeeded, job.Status.Failed)
+
+	condition.ObservedGeneration = job.Generation
+
+	var complete, failed *batchv1.JobCondition
+
+	for index := range job.Status.Conditions {
+		if job.Status.Conditions[index].Status != corev1.ConditionTrue {
+			continue
+		}
+
+		switch job.Status.Conditions[index].Type {
+		case batchv1.JobComplete:
+			complete = &job.Status.Conditions[index]
+		case batchv1.JobFailed:
+			failed = &job.Status.Conditions[index]
+		}
+	}
+
+	switch {
+	case complete != nil:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = flarev1alpha1.IntentWorkloadReasonSucceeded
+		condition.Message = pods
+	case failed != nil:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = flarev1alpha1.IntentWorkloadReasonFailed
+		condition.Message = failed.Message + " (" + pods + ")"
+	case job.Status.Active > 0:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = flarev1alpha1.IntentWorkloadReasonRunning
+		condition.Message = pods
+	default:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = flarev1alpha1.IntentWorkloadReasonPending
+		condition.Message = pods
+	}
+}
+
+func deploymentWorkloadCondition(deployment *appsv1.Deployment, condition *metav1.Condition) {
+	pods := fmt.Sprintf("%d/%d ready, %d updated, %d available pods", deployment.Status.ReadyReplicas, ptr.Deref(deployment.Spec.Replicas, 1), deployment.Status.UpdatedReplicas, deployment.Status.AvailableReplicas)
+
+	condition.ObservedGeneration = deployment.Generation
+
+	var progressing, available *appsv1.DeploymentCondition
+
+	for index := range deployment.Status.Conditions {
+		switch deployment.Status.Conditions[index].Type {
+		case appsv1.DeploymentProgressing:
+			progressing = &deployment.Status.Conditions[index]
+		case appsv1.DeploymentAvailable:
+			available = &deployment.Status.Conditions[index]
+		}
+	}
+
+	switch {
+	case progressing != nil && progressing.Status == corev1.ConditionFalse:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = flarev1alpha1.IntentWorkloadReasonFailed
+		condition.Message = progressing.Message + " (" + pods + ")"
+	case available != nil && available.Status == corev1.ConditionTrue:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = flarev1alpha1.IntentWorkloadReasonRunning
+		condition.Message = pods
+	default:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = flarev1alpha1.IntentWorkloadReasonPending
+		condition.Message = pods
+	}
+}
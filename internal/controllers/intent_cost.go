@@ -0,0 +1,72 @@
+// Copyright 2025 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	flarev1alpha1 "github.com/clastix/flare-internal/api/v1alpha1"
+	"github.com/clastix/flare-internal/internal/flavor"
+)
+
+// costMeteringInterval is the minimum interval between two meterings,
+// preventing the status update to trigger a new reconciliation at every run.
+const costMeteringInterval = time.Minute
+
+// MeterCost accrues the cost of the allocation since the last metering, as long as its FLUIDOS Contract is held,
+// and tracks the hourly rate agreed in the Contract: once the budget is exceeded, the cost is not metered anymore,
+// bounding it until the budget is raised.
+// The carbon emissions are accrued upon the emission rate estimated for the Contract Flavor, while the workload is running.
+func (i *IntentReconciler) MeterCost(ctx context.Context, intent *flarev1alpha1.Intent) error {
+	now := metav1.Now()
+
+	rate, emissionRate := intent.Status.Cost.HourlyRate, intent.Status.Emissions.Rate
+
+	contract, _, _ := i.retrieveContract(ctx, intent, intent.Status.Allocation.Generation)
+	if contract != nil {
+		if hourlyRate, ok := flavor.HourlyRate(contract.Spec.Flavor); ok {
+			rate = hourlyRate
+		}
+
+		if estimated, ok := flavor.EmissionRate(contract.Spec.Flavor); ok {
+			emissionRate = estimated
+		}
+	}
+
+	exceeded := budgetExceeded(intent)
+	// The zero rate stops the extrapolation of the current cost as well.
+	if exceeded {
+		rate = 0
+	}
+
+	lastMetering := intent.Status.Cost.LastMeteringTime
+	if lastMetering != nil && rate == intent.Status.Cost.HourlyRate && emissionRate == intent.Status.Emissions.Rate && now.Sub(lastMetering.Time) < costMeteringInterval {
+		return nil
+	}
+
+	// The suspended workload still holds its Reservation, Contract and peering, which are paid for,
+	// while it emits no carbon.
+	suspended := workloadSuspended(intent)
+
+	return UpdateStatus(ctx, i.Client, intent, func(status *flarev1alpha1.IntentStatus) {
+		if status.Cost.LastMeteringTime != nil {
+			hours := now.Sub(status.Cost.LastMeteringTime.Time).Hours()
+
+			if contract != nil && !exceeded {
+				status.Cost.Accrued += status.Cost.HourlyRate * hours
+			}
+
+			if contract != nil && !suspended {
+				status.Emissions.Accrued += status.Emissions.Rate * hours / 1000
+			}
+		}
+
+		status.Cost.HourlyRate = rate
+		status.Emissions.Rate = emissionRate
+		status.Cost.LastMeteringTime = &now
+	})
+}
@@ -8,26 +8,43 @@ import (
 	"strconv"
 	"strings"
 
+	fluidosconsts "github.com/fluidos-project/node/pkg/utils/consts"
+	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	v1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
-	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	flarev1alpha1 "github.com/clastix/flare-internal/api/v1alpha1"
 )
 
 func (i *IntentReconciler) HandleKubernetesObjects(ctx context.Context, intent *flarev1alpha1.Intent) error {
+	if err := i.HandleNetworkPolicies(ctx, intent); err != nil {
+		return err
+	}
+
 	condition := meta.FindStatusCondition(intent.Status.Conditions, flarev1alpha1.IntentStatusTypeDeploy).DeepCopy()
 
 	condition.Status = metav1.ConditionTrue
 	condition.Reason = "KubernetesObjectsHandled"
 	condition.Message = ""
 
+	if err := i.kubernetesSecret(ctx, intent); err != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "SecretCreationFailed"
+		condition.Message = err.Error()
+
+		return UpdateStatusCondition(ctx, i.Client, intent, *condition)
+	}
+
 	switch intent.Spec.Workload.Type {
 	case flarev1alpha1.IntentWorkloadTypeService:
 		if err := i.kubernetesService(ctx, intent); err != nil {
@@ -38,124 +55,117 @@ func (i *IntentReconciler) HandleKubernetesObjects(ctx context.Context, intent *
 			break
 		}
 
-		if err := i.kubernetesIngress(ctx, intent); err != nil {
+		if err := i.kubernetesDeployment(ctx, intent); err != nil {
 			condition.Status = metav1.ConditionFalse
-			condition.Reason = "IngressCreationFailed"
+			condition.Reason = "DeploymentCreationFailed"
 			condition.Message = err.Error()
 
 			break
 		}
 
-		if err := i.kubernetesDeployment(ctx, intent); err != nil {
+		if err := i.kubernetesHorizontalPodAutoscaler(ctx, intent); err != nil {
 			condition.Status = metav1.ConditionFalse
-			condition.Reason = "DeploymentCreationFailed"
+			condition.Reason = "HorizontalPodAutoscalerCreationFailed"
 			condition.Message = err.Error()
 
 			break
 		}
-	case flarev1alpha1.IntentWorkloadTypeBatch:
-		if err := i.kubernetesJob(ctx, intent); err != nil {
+		// The workload runs regardless of its exposure, reconciled last.
+		if err := i.kubernetesExposure(ctx, intent); err != nil {
 			condition.Status = metav1.ConditionFalse
-			condition.Reason = "DeploymentCreationFailed"
+			condition.Reason = "ExposureFailed"
 			condition.Message = err.Error()
 
 			break
 		}
-	}
-
-	return UpdateStatusCondition(ctx, i.Client, intent, *condition)
-}
+	case flarev1alpha1.IntentWorkloadTypeBatch:
+		if err := i.kubernetesHeadlessService(ctx, intent); err != nil {
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = "ServiceCreationFailed"
+			condition.Message = err.Error()
 
-//+kubebuilder:rbac:groups="networking.k8s.io",resources=ingresses,verbs=create;get;list;watch;update
+			break
+		}
 
-func (i *IntentReconciler) kubernetesIngress(ctx context.Context, intent *flarev1alpha1.Intent) error {
+		recreating, err := i.kubernetesJob(ctx, intent)
+		if err != nil {
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = "DeploymentCreationFailed"
+			condition.Message = err.Error()
 
-	var exposed []flarev1alpha1.IntentWorkloadPort
-	for _, port := range intent.Spec.Workload.Ports {
-		if port.Expose {
-			exposed = append(exposed, port)
+			break
+		}
+		// The deleted Job triggers a new reconciliation through its watch, recreating it.
+		if recreating {
+			condition.Status = metav1.ConditionUnknown
+			condition.Reason = "JobRecreating"
+			condition.Message = "Job has been deleted to apply the workload changes, waiting for its recreation"
 		}
 	}
 
-	if len(exposed) == 0 {
-		return nil
-	}
-
-	var ingress networkingv1.Ingress
-	ingress.Name = intent.Namespace
-	ingress.Namespace = intent.Namespace
-
-	_, err := controllerutil.CreateOrUpdate(ctx, i.Client, &ingress, func() error {
-		if len(ingress.Spec.Rules) != len(exposed) {
-			ingress.Spec.Rules = make([]networkingv1.IngressRule, len(exposed))
-		}
+	return UpdateStatusCondition(ctx, i.Client, intent, *condition)
+}
 
-		for index, port := range exposed {
-			ingress.Spec.Rules[index].Host = port.Domain
+// jobSpecHashAnnotation tracks the immutable Job spec fields a Job has been created with.
+const jobSpecHashAnnotation = "flare.clastix.io/job-spec-hash"
 
-			if ingress.Spec.Rules[index].HTTP == nil {
-				ingress.Spec.Rules[index].HTTP = &networkingv1.HTTPIngressRuleValue{}
-			}
+//+kubebuilder:rbac:groups="batch",resources=jobs,verbs=create;get;list;watch;update;patch;delete
 
-			ingress.Spec.Rules[index].HTTP.Paths = []networkingv1.HTTPIngressPath{
-				{
-					Path:     "/",
-					PathType: ptr.To(networkingv1.PathTypePrefix),
-					Backend: networkingv1.IngressBackend{
-						Service: &networkingv1.IngressServiceBackend{
-							Name: intent.Namespace,
-							Port: networkingv1.ServiceBackendPort{
-								Number: port.Port,
-							},
-						},
-						Resource: nil,
-					},
-				},
-			}
-		}
-
-		return controllerutil.SetOwnerReference(intent, &ingress, i.Client.Scheme())
-	})
+// kubernetesJob creates or updates the workload Job, returning true when it has been deleted to be recreated with the workload changes.
+func (i *IntentReconciler) kubernetesJob(ctx context.Context, intent *flarev1alpha1.Intent) (bool, error) {
+	immutable, err := i.jobImmutableSpec(intent)
 	if err != nil {
-		return err
+		return false, err
 	}
 
-	return nil
-}
+	hash := specHash(immutable)
 
-//+kubebuilder:rbac:groups="batch",resources=jobs,verbs=create;get;list;watch;update
-
-func (i *IntentReconciler) kubernetesJob(ctx context.Context, intent *flarev1alpha1.Intent) error {
 	var job v1.Job
 	job.Name = intent.Namespace
 	job.Namespace = intent.Namespace
 
-	_, err := controllerutil.CreateOrUpdate(ctx, i.Client, &job, func() error {
-		job.Spec.Selector = &metav1.LabelSelector{
-			MatchLabels: map[string]string{
-				"intent": intent.Name,
-			},
+	// The Job pod template, and its completions, are immutable: it's replaced upon their changes.
+	err = i.Client.Get(ctx, client.ObjectKeyFromObject(&job), &job)
+	switch {
+	case apierrors.IsNotFound(err):
+		break
+	case err != nil:
+		return false, err
+	case job.Annotations[jobSpecHashAnnotation] == "":
+		// Jobs created before the tracking of the immutable fields are adopted as they are.
+		patch := client.MergeFrom(job.DeepCopy())
+		metav1.SetMetaDataAnnotation(&job.ObjectMeta, jobSpecHashAnnotation, hash)
+
+		return false, i.Client.Patch(ctx, &job, patch)
+	case job.Annotations[jobSpecHashAnnotation] != hash:
+		if err = i.Client.Delete(ctx, &job, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !apierrors.IsNotFound(err) {
+			return false, err
 		}
 
+		return true, nil
+	}
+
+	_, err = controllerutil.CreateOrUpdate(ctx, i.Client, &job, func() error {
+		metav1.SetMetaDataAnnotation(&job.ObjectMeta, jobSpecHashAnnotation, hash)
+
+		// The selector is left to the API Server, generated upon the Job controller unique label.
 		job.Spec.BackoffLimit = ptr.To(int32(intent.Spec.Workload.Batch.MaxRetries))
 		job.Spec.Parallelism = ptr.To(int32(intent.Spec.Workload.Batch.ParallelTasks))
 		job.Spec.ActiveDeadlineSeconds = ptr.To(int64(intent.Spec.Workload.Batch.Timeout.Duration.Seconds()))
-
-		if intent.Spec.Workload.Batch.CompletionPolicy == "All" {
-			job.Spec.Completions = ptr.To(int32(intent.Spec.Workload.Batch.ParallelTasks))
-		}
-
-		if err := i.kubernetesPodTemplate(&job.Spec.Template, intent); err != nil {
-			return err
+		// Suspending the Job terminates its active Pods, which are recreated upon resume.
+		job.Spec.Suspend = ptr.To(workloadSuspended(intent))
+
+		// The immutable fields are set upon creation only, the API Server labelling the pod template.
+		if job.CreationTimestamp.IsZero() {
+			job.Spec.Completions = immutable.Completions
+			job.Spec.CompletionMode = immutable.CompletionMode
+			job.Spec.Template = immutable.Template
 		}
 
 		return controllerutil.SetOwnerReference(intent, &job, i.Client.Scheme())
 	})
-	if err != nil {
-		return err
-	}
 
-	return nil
+	return false, err
 }
 
 //+kubebuilder:rbac:groups="apps",resources=deployments,verbs=create;get;list;watch;update
@@ -166,7 +176,15 @@ func (i *IntentReconciler) kubernetesDeployment(ctx context.Context, intent *fla
 	deployment.Namespace = intent.Namespace
 
 	_, err := controllerutil.CreateOrUpdate(ctx, i.Client, &deployment, func() error {
-		deployment.Spec.Replicas = ptr.To(int32(1))
+		// When autoscaling is enabled the replicas are owned by the HorizontalPodAutoscaler,
+		// the minimum amount is used only as the initial value upon creation, or upon resume.
+		switch {
+		case workloadSuspended(intent):
+			deployment.Spec.Replicas = ptr.To(int32(0))
+		case !intent.Spec.Workload.Scaling.AutoScale || ptr.Deref(deployment.Spec.Replicas, 0) == 0:
+			deployment.Spec.Replicas = ptr.To(minReplicas(intent))
+		}
+
 		deployment.Spec.Selector = &metav1.LabelSelector{
 			MatchLabels: map[string]string{
 				"intent": intent.Name,
@@ -182,11 +200,162 @@ func (i *IntentReconciler) kubernetesDeployment(ctx context.Context, intent *fla
 	return err
 }
 
+//+kubebuilder:rbac:groups="autoscaling",resources=horizontalpodautoscalers,verbs=create;get;list;watch;update;delete
+
+func (i *IntentReconciler) kubernetesHorizontalPodAutoscaler(ctx context.Context, intent *flarev1alpha1.Intent) error {
+	var hpa autoscalingv2.HorizontalPodAutoscaler
+	hpa.Name = intent.Namespace
+	hpa.Namespace = intent.Namespace
+
+	// The HorizontalPodAutoscaler would scale up the suspended Deployment.
+	if !intent.Spec.Workload.Scaling.AutoScale || workloadSuspended(intent) {
+		if err := i.Client.Delete(ctx, &hpa); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+
+		return nil
+	}
+
+	metrics := i.horizontalPodAutoscalerMetrics(intent)
+	// The HorizontalPodAutoscaler with no metrics would silently fall back to the default CPU target.
+	if len(metrics) == 0 {
+		if err := i.Client.Delete(ctx, &hpa); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+
+		return errors.New("no autoscaling metric: the CPU target, or the CPU resources, are not set, and the GPU utilization metric is not available")
+	}
+
+	_, err := controllerutil.CreateOrUpdate(ctx, i.Client, &hpa, func() error {
+		hpa.Spec.ScaleTargetRef = autoscalingv2.CrossVersionObjectReference{
+			APIVersion: appsv1.SchemeGroupVersion.String(),
+			Kind:       "Deployment",
+			Name:       intent.Namespace,
+		}
+		hpa.Spec.MinReplicas = ptr.To(minReplicas(intent))
+		hpa.Spec.MaxReplicas = max(int32(intent.Spec.Workload.Scaling.MaxReplicas), *hpa.Spec.MinReplicas)
+		hpa.Spec.Metrics = metrics
+
+		return controllerutil.SetOwnerReference(intent, &hpa, i.Client.Scheme())
+	})
+
+	return err
+}
+
+// horizontalPodAutoscalerMetrics returns the metrics the workload is scaled upon, if their target is set.
+func (i *IntentReconciler) horizontalPodAutoscalerMetrics(intent *flarev1alpha1.Intent) []autoscalingv2.MetricSpec {
+	metrics := make([]autoscalingv2.MetricSpec, 0, 2)
+
+	// The CPU utilization is computed upon the container request, missing when no CPU is required.
+	if intent.Spec.Workload.Scaling.TargetCpuPercent > 0 && !intent.Spec.Workload.Resources.CPU.IsZero() {
+		metrics = append(metrics, autoscalingv2.MetricSpec{
+			Type: autoscalingv2.ResourceMetricSourceType,
+			Resource: &autoscalingv2.ResourceMetricSource{
+				Name: corev1.ResourceCPU,
+				Target: autoscalingv2.MetricTarget{
+					Type:               autoscalingv2.UtilizationMetricType,
+					AverageUtilization: ptr.To(int32(intent.Spec.Workload.Scaling.TargetCpuPercent)),
+				},
+			},
+		})
+	}
+
+	// GPU utilization is not a native resource metric:
+	// it's exposed per Pod by the GPU exporter through the custom metrics API.
+	if intent.Spec.Workload.Scaling.TargetGpuPercent > 0 && intent.Spec.Workload.Resources.GPU.Count > 0 && i.GPUUtilizationMetric != "" {
+		metrics = append(metrics, autoscalingv2.MetricSpec{
+			Type: autoscalingv2.PodsMetricSourceType,
+			Pods: &autoscalingv2.PodsMetricSource{
+				Metric: autoscalingv2.MetricIdentifier{
+					Name: i.GPUUtilizationMetric,
+				},
+				Target: autoscalingv2.MetricTarget{
+					Type:         autoscalingv2.AverageValueMetricType,
+					AverageValue: resource.NewQuantity(int64(intent.Spec.Workload.Scaling.TargetGpuPercent), resource.DecimalSI),
+				},
+			},
+		})
+	}
+
+	return metrics
+}
+
+// workloadSuspended returns true when the workload must not run,
+// since the Intent budget is exhausted, or it's outside its availability windows.
+func workloadSuspended(intent *flarev1alpha1.Intent) bool {
+	return budgetExceeded(intent) || outsideAvailability(intent)
+}
+
+func minReplicas(intent *flarev1alpha1.Intent) int32 {
+	return max(int32(intent.Spec.Workload.Scaling.MinReplicas), 1)
+}
+
+// jobImmutableSpec returns the immutable Job spec fields rendered for the Intent workload.
+func (i *IntentReconciler) jobImmutableSpec(intent *flarev1alpha1.Intent) (v1.JobSpec, error) {
+	var spec v1.JobSpec
+
+	if intent.Spec.Workload.Batch.CompletionPolicy == "All" {
+		spec.Completions = ptr.To(int32(intent.Spec.Workload.Batch.ParallelTasks))
+	}
+
+	// Each distributed training rank is a completion index, and all of them must succeed.
+	if distributedTraining(intent) {
+		spec.CompletionMode = ptr.To(v1.IndexedCompletion)
+		spec.Completions = ptr.To(int32(intent.Spec.Workload.Batch.ParallelTasks))
+	}
+
+	if err := i.kubernetesPodTemplate(&spec.Template, intent); err != nil {
+		return spec, err
+	}
+
+	return spec, nil
+}
+
 func (i *IntentReconciler) kubernetesPodTemplate(podTemplate *corev1.PodTemplateSpec, intent *flarev1alpha1.Intent) error {
 	podTemplate.Labels = map[string]string{
 		"intent": intent.Name,
 	}
 
+	// The NamespaceOffloading selects the provider clusters of all the allocations during a migration:
+	// the workload is pinned to the running one, and moved once the migration has been promoted.
+	podTemplate.Spec.Affinity = nil
+
+	if clusterID := intent.Status.Allocation.ClusterID; clusterID != "" {
+		podTemplate.Spec.Affinity = &corev1.Affinity{
+			NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{
+						{
+							MatchExpressions: []corev1.NodeSelectorRequirement{
+								{
+									Key:      fluidosconsts.LiqoRemoteClusterIDLabel,
+									Operator: corev1.NodeSelectorOpIn,
+									Values:   []string{clusterID},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	podTemplate.Spec.Affinity = strategyAffinity(podTemplate.Spec.Affinity, intent)
+
+	// The Jobs retry the failed Pods upon their backoff limit, rather than restarting their containers.
+	podTemplate.Spec.RestartPolicy = corev1.RestartPolicyAlways
+
+	if intent.Spec.Workload.Type == flarev1alpha1.IntentWorkloadTypeBatch {
+		podTemplate.Spec.RestartPolicy = corev1.RestartPolicyNever
+	}
+
+	// The ranks of the distributed training are resolved through the headless Service.
+	podTemplate.Spec.Subdomain = ""
+
+	if distributedTraining(intent) {
+		podTemplate.Spec.Subdomain = intent.Namespace
+	}
+
 	if len(podTemplate.Spec.Containers) != 1 {
 		podTemplate.Spec.Containers = make([]corev1.Container, 1)
 	}
@@ -194,9 +363,14 @@ func (i *IntentReconciler) kubernetesPodTemplate(podTemplate *corev1.PodTemplate
 	podTemplate.Spec.Containers[0].Name = intent.Spec.Workload.Name
 	podTemplate.Spec.Containers[0].Image = intent.Spec.Workload.Image
 	podTemplate.Spec.Containers[0].Command = intent.Spec.Workload.Commands
+	podTemplate.Spec.Containers[0].Resources = i.containerResources(intent)
 	podTemplate.Spec.Containers[0].Env = func() []corev1.EnvVar {
 		out := make([]corev1.EnvVar, 0, len(intent.Spec.Workload.Env)+len(intent.Spec.Workload.Secrets))
 
+		if distributedTraining(intent) {
+			out = append(out, distributedEnv(intent)...)
+		}
+
 		for _, env := range intent.Spec.Workload.Env {
 			var envVar corev1.EnvVar
 
@@ -233,7 +407,7 @@ func (i *IntentReconciler) kubernetesPodTemplate(podTemplate *corev1.PodTemplate
 	}()
 
 	if len(podTemplate.Spec.Containers[0].VolumeMounts) != len(intent.Spec.Workload.Storage.Volumes) {
-		podTemplate.Spec.Containers[0].VolumeMounts = make([]corev1.VolumeMount, 0, len(intent.Spec.Workload.Storage.Volumes))
+		podTemplate.Spec.Containers[0].VolumeMounts = make([]corev1.VolumeMount, len(intent.Spec.Workload.Storage.Volumes))
 	}
 
 	if len(podTemplate.Spec.Volumes) != len(intent.Spec.Workload.Storage.Volumes) {
@@ -255,16 +429,11 @@ func (i *IntentReconciler) kubernetesPodTemplate(podTemplate *corev1.PodTemplate
 
 				return nil
 			}(),
-			CSI: func() *corev1.CSIVolumeSource {
+			// Persistent volumes are backed by the PersistentVolumeClaims provisioned in the storage phase.
+			PersistentVolumeClaim: func() *corev1.PersistentVolumeClaimVolumeSource {
 				if volume.Type == "Persistent" {
-					return &corev1.CSIVolumeSource{
-						Driver: volume.Source.Type,
-						VolumeAttributes: map[string]string{
-							"uri": volume.Source.Uri,
-						},
-						NodePublishSecretRef: &corev1.LocalObjectReference{
-							Name: volume.Source.Credentials,
-						},
+					return &corev1.PersistentVolumeClaimVolumeSource{
+						ClaimName: volume.Name,
 					}
 				}
 
@@ -276,6 +445,33 @@ func (i *IntentReconciler) kubernetesPodTemplate(podTemplate *corev1.PodTemplate
 	return nil
 }
 
+// containerResources returns the resources requested by the workload container, limited to the same amount:
+// the HorizontalPodAutoscaler computes the CPU utilization upon the request.
+func (i *IntentReconciler) containerResources(intent *flarev1alpha1.Intent) corev1.ResourceRequirements {
+	resources := corev1.ResourceList{}
+
+	if !intent.Spec.Workload.Resources.CPU.IsZero() {
+		resources[corev1.ResourceCPU] = intent.Spec.Workload.Resources.CPU
+	}
+
+	if !intent.Spec.Workload.Resources.Memory.IsZero() {
+		resources[corev1.ResourceMemory] = intent.Spec.Workload.Resources.Memory
+	}
+
+	if count := intent.Spec.Workload.Resources.GPU.Count; count > 0 && i.GPUResourceName != "" {
+		resources[corev1.ResourceName(i.GPUResourceName)] = *resource.NewQuantity(count, resource.DecimalSI)
+	}
+
+	if len(resources) == 0 {
+		return corev1.ResourceRequirements{}
+	}
+
+	return corev1.ResourceRequirements{
+		Requests: resources,
+		Limits:   resources.DeepCopy(),
+	}
+}
+
 //+kubebuilder:rbac:groups="",resources=services,verbs=create;get;list;watch;update
 
 func (i *IntentReconciler) kubernetesService(ctx context.Context, intent *flarev1alpha1.Intent) error {
@@ -0,0 +1,135 @@
+// Copyright 2025 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	flarev1alpha1 "github.com/clastix/flare-internal/api/v1alpha1"
+)
+
+// distributedMasterPort is the port the rank 0 Pod listens on for the distributed training rendezvous,
+// the PyTorch default one.
+const distributedMasterPort = 29500
+
+// distributedTraining returns true when the batch workload runs as a set of communicating ranks,
+// rendered as an indexed Job, one rank per parallel task.
+func distributedTraining(intent *flarev1alpha1.Intent) bool {
+	workload := intent.Spec.Workload
+
+	return workload.Type == flarev1alpha1.IntentWorkloadTypeBatch && workload.Batch.ParallelTasks > 1 &&
+		(workload.CommunicationPattern == "AllReduce" || workload.CommunicationPattern == "Pipeline")
+}
+
+// distributedEnv returns the environment variables of the distributed training rank,
+// as expected by the PyTorch distributed launcher: the rank is the Pod completion index,
+// and the rank 0 Pod is resolved through the headless Service.
+func distributedEnv(intent *flarev1alpha1.Intent) []corev1.EnvVar {
+	return []corev1.EnvVar{
+		{
+			Name: "RANK",
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					FieldPath: "metadata.annotations['batch.kubernetes.io/job-completion-index']",
+				},
+			},
+		},
+		{
+			Name:  "WORLD_SIZE",
+			Value: strconv.Itoa(intent.Spec.Workload.Batch.ParallelTasks),
+		},
+		{
+			// Pods of an indexed Job are named after the Job, and the Job after the Intent Namespace.
+			Name:  "MASTER_ADDR",
+			Value: intent.Namespace + "-0." + intent.Namespace,
+		},
+		{
+			Name:  "MASTER_PORT",
+			Value: strconv.Itoa(distributedMasterPort),
+		},
+	}
+}
+
+// strategyAffinity translates the Intent deployment strategy into the Pods affinity:
+// colocated Pods are required on the same node, while distributed ones are preferably spread across nodes.
+func strategyAffinity(affinity *corev1.Affinity, intent *flarev1alpha1.Intent) *corev1.Affinity {
+	term := corev1.PodAffinityTerm{
+		LabelSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				"intent": intent.Name,
+			},
+		},
+		TopologyKey: corev1.LabelHostname,
+	}
+
+	switch intent.Spec.Workload.DeploymentStrategy {
+	case "Colocated":
+		if affinity == nil {
+			affinity = &corev1.Affinity{}
+		}
+
+		affinity.PodAffinity = &corev1.PodAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{term},
+		}
+	case "Distributed":
+		if affinity == nil {
+			affinity = &corev1.Affinity{}
+		}
+
+		affinity.PodAntiAffinity = &corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+				{
+					Weight:          100,
+					PodAffinityTerm: term,
+				},
+			},
+		}
+	}
+
+	return affinity
+}
+
+//+kubebuilder:rbac:groups="",resources=services,verbs=create;get;list;watch;update;delete
+
+// kubernetesHeadlessService manages the headless Service giving a stable DNS name to the distributed training ranks:
+// it's removed once the batch workload is not distributed anymore.
+func (i *IntentReconciler) kubernetesHeadlessService(ctx context.Context, intent *flarev1alpha1.Intent) error {
+	var svc corev1.Service
+	svc.Name = intent.Namespace
+	svc.Namespace = intent.Namespace
+
+	if !distributedTraining(intent) {
+		if err := i.Client.Delete(ctx, &svc); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+
+		return nil
+	}
+
+	_, err := controllerutil.CreateOrUpdate(ctx, i.Client, &svc, func() error {
+		svc.Spec.ClusterIP = corev1.ClusterIPNone
+		// Ranks must resolve each other before being ready, during the rendezvous.
+		svc.Spec.PublishNotReadyAddresses = true
+		svc.Spec.Selector = map[string]string{
+			"intent": intent.Name,
+		}
+		svc.Spec.Ports = []corev1.ServicePort{
+			{
+				Name:     "rendezvous",
+				Protocol: corev1.ProtocolTCP,
+				Port:     distributedMasterPort,
+			},
+		}
+
+		return controllerutil.SetOwnerReference(intent, &svc, i.Client.Scheme())
+	})
+
+	return err
+}
@@ -0,0 +1,506 @@
+// Copyright 2025 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	flarev1alpha1 "github.com/clastix/flare-internal/api/v1alpha1"
+	"github.com/clastix/flare-internal/internal/indexer"
+)
+
+// Exposure backends selectable for the installation.
+const (
+	// ExposureBackendIngress exposes the HTTP ports through an Ingress, and the other ones through a LoadBalancer Service.
+	ExposureBackendIngress = "ingress"
+	// ExposureBackendLoadBalancer exposes every port through a LoadBalancer Service.
+	ExposureBackendLoadBalancer = "loadbalancer"
+	// ExposureBackendGateway exposes the ports through the Gateway API routes attached to the configured Gateway.
+	ExposureBackendGateway = "gateway"
+)
+
+// exposure is a backend exposing the workload ports out of the cluster.
+type exposure interface {
+	// expose reconciles the objects exposing the given ports, returning the endpoints they're reachable at:
+	// the objects are removed when no port is given.
+	expose(ctx context.Context, intent *flarev1alpha1.Intent, ports []flarev1alpha1.IntentWorkloadPort) ([]flarev1alpha1.IntentEndpoint, error)
+}
+
+// httpPort returns true when the port is served over HTTP, the default for the TCP ports.
+func httpPort(port flarev1alpha1.IntentWorkloadPort) bool {
+	return port.Protocol != "UDP" && port.AppProtocol != "TCP"
+}
+
+// portScheme returns the URL scheme of the port exposed at the given address rather than at a domain.
+func portScheme(port flarev1alpha1.IntentWorkloadPort) string {
+	switch {
+	case httpPort(port):
+		return "http"
+	case port.Protocol == "UDP":
+		return "udp"
+	default:
+		return "tcp"
+	}
+}
+
+// kubernetesExposure exposes the ports through the configured backend, removing the objects of the unused ones,
+// and tracks the resulting endpoints in the Intent status.
+func (i *IntentReconciler) kubernetesExposure(ctx context.Context, intent *flarev1alpha1.Intent) error {
+	var exposed, httpPorts, otherPorts []flarev1alpha1.IntentWorkloadPort
+
+	var claimed []string
+
+	for _, port := range intent.Spec.Workload.Ports {
+		if !port.Expose {
+			continue
+		}
+
+		if port.Domain != "" {
+			taken, err := i.domainClaimed(ctx, intent, port.Domain)
+			if err != nil {
+				return err
+			}
+
+			if taken {
+				claimed = append(claimed, port.Domain)
+
+				continue
+			}
+		}
+
+		exposed = append(exposed, port)
+
+		if httpPort(port) {
+			httpPorts = append(httpPorts, port)
+		} else {
+			otherPorts = append(otherPorts, port)
+		}
+	}
+
+	type backend struct {
+		exposure exposure
+		ports    []flarev1alpha1.IntentWorkloadPort
+	}
+
+	ingress, loadBalancer := &ingressExposure{i}, &loadBalancerExposure{i}
+	// The Ingress serves HTTP only, the other ports are exposed through a LoadBalancer Service.
+	backends := []backend{{ingress, httpPorts}, {loadBalancer, otherPorts}}
+
+	switch i.ExposureBackend {
+	case ExposureBackendLoadBalancer:
+		backends = []backend{{ingress, nil}, {loadBalancer, exposed}}
+	case ExposureBackendGateway:
+		backends = []backend{{ingress, nil}, {loadBalancer, nil}, {&gatewayExposure{i}, exposed}}
+	}
+
+	var endpoints []flarev1alpha1.IntentEndpoint
+
+	for _, b := range backends {
+		result, err := b.exposure.expose(ctx, intent, b.ports)
+		if err != nil {
+			return err
+		}
+
+		endpoints = append(endpoints, result...)
+	}
+
+	slices.SortFunc(endpoints, func(a, b flarev1alpha1.IntentEndpoint) int {
+		return cmp.Compare(a.Port, b.Port)
+	})
+
+	if !slices.Equal(intent.Status.Endpoints, endpoints) {
+		if err := UpdateStatus(ctx, i.Client, intent, func(status *flarev1alpha1.IntentStatus) {
+			status.Endpoints = endpoints
+		}); err != nil {
+			return err
+		}
+	}
+
+	if len(claimed) > 0 {
+		return fmt.Errorf("domains already claimed by another Intent are not exposed: %s", strings.Join(claimed, ", "))
+	}
+
+	return nil
+}
+
+// domainClaimed returns true when the custom domain is claimed by another Intent created before,
+// breaking the ties upon the UID: the domain is exposed only for the Intent claiming it first.
+func (i *IntentReconciler) domainClaimed(ctx context.Context, intent *flarev1alpha1.Intent, domain string) (bool, error) {
+	var intentList flarev1alpha1.IntentList
+	if err := i.Client.List(ctx, &intentList, client.MatchingFields{indexer.IntentDomain{}.Field(): domain}); err != nil {
+		return false, errors.Wrap(err, "cannot retrieve the Intents claiming domain "+domain)
+	}
+
+	for _, other := range intentList.Items {
+		if other.UID == intent.UID {
+			continue
+		}
+
+		if other.CreationTimestamp.Before(&intent.CreationTimestamp) || (other.CreationTimestamp.Equal(&intent.CreationTimestamp) && other.UID < intent.UID) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// exposedHost returns the domain the exposed port is reachable at: ports with no custom domain
+// are assigned one under the wildcard domain, named after the Intent Namespace.
+// An empty domain is returned when no wildcard domain is configured, the port being routed regardless of the host.
+func (i *IntentReconciler) exposedHost(intent *flarev1alpha1.Intent, port flarev1alpha1.IntentWorkloadPort) string {
+	switch {
+	case port.Domain != "":
+		return port.Domain
+	case i.IngressDomain != "":
+		return fmt.Sprintf("%s-%d.%s", intent.Namespace, port.Port, i.IngressDomain)
+	default:
+		return ""
+	}
+}
+
+// certManagerClusterIssuerAnnotation requests cert-manager to issue the Ingress TLS certificates from the ClusterIssuer.
+const certManagerClusterIssuerAnnotation = "cert-manager.io/cluster-issuer"
+
+// ingressExposure exposes the HTTP ports through an Ingress of the configured class, a rule for each of them:
+// when a cert-manager ClusterIssuer is configured, a TLS certificate is issued for each port in its own Secret.
+type ingressExposure struct {
+	*IntentReconciler
+}
+
+//+kubebuilder:rbac:groups="networking.k8s.io",resources=ingresses,verbs=create;get;list;watch;update;delete
+
+func (i *ingressExposure) expose(ctx context.Context, intent *flarev1alpha1.Intent, ports []flarev1alpha1.IntentWorkloadPort) ([]flarev1alpha1.IntentEndpoint, error) {
+	var ingress networkingv1.Ingress
+	ingress.Name = intent.Namespace
+	ingress.Namespace = intent.Namespace
+
+	if len(ports) == 0 {
+		if err := i.Client.Delete(ctx, &ingress); err != nil && !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+
+	scheme := "http"
+	if i.IngressClusterIssuer != "" {
+		scheme = "https"
+	}
+
+	hosts := make([]string, len(ports))
+
+	for index, port := range ports {
+		hosts[index] = i.exposedHost(intent, port)
+	}
+
+	_, err := controllerutil.CreateOrUpdate(ctx, i.Client, &ingress, func() error {
+		if i.IngressClass != "" {
+			ingress.Spec.IngressClassName = ptr.To(i.IngressClass)
+		}
+
+		if i.IngressClusterIssuer != "" {
+			if ingress.Annotations == nil {
+				ingress.Annotations = map[string]string{}
+			}
+
+			ingress.Annotations[certManagerClusterIssuerAnnotation] = i.IngressClusterIssuer
+		} else {
+			delete(ingress.Annotations, certManagerClusterIssuerAnnotation)
+		}
+
+		if len(ingress.Spec.Rules) != len(ports) {
+			ingress.Spec.Rules = make([]networkingv1.IngressRule, len(ports))
+		}
+
+		ingress.Spec.TLS = nil
+
+		for index, port := range ports {
+			ingress.Spec.Rules[index].Host = hosts[index]
+
+			if ingress.Spec.Rules[index].HTTP == nil {
+				ingress.Spec.Rules[index].HTTP = &networkingv1.HTTPIngressRuleValue{}
+			}
+
+			ingress.Spec.Rules[index].HTTP.Paths = []networkingv1.HTTPIngressPath{
+				{
+					Path:     "/",
+					PathType: ptr.To(networkingv1.PathTypePrefix),
+					Backend: networkingv1.IngressBackend{
+						Service: &networkingv1.IngressServiceBackend{
+							Name: intent.Namespace,
+							Port: networkingv1.ServiceBackendPort{
+								Number: port.Port,
+							},
+						},
+						Resource: nil,
+					},
+				},
+			}
+
+			// No certificate can be issued for the rules with no host.
+			if i.IngressClusterIssuer != "" && hosts[index] != "" {
+				ingress.Spec.TLS = append(ingress.Spec.TLS, networkingv1.IngressTLS{
+					Hosts:      []string{hosts[index]},
+					SecretName: fmt.Sprintf("%s-%d-tls", intent.Namespace, port.Port),
+				})
+			}
+		}
+
+		return controllerutil.SetOwnerReference(intent, &ingress, i.Client.Scheme())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var address string
+	for _, lb := range ingress.Status.LoadBalancer.Ingress {
+		if address = cmp.Or(lb.IP, lb.Hostname); address != "" {
+			break
+		}
+	}
+
+	endpoints := make([]flarev1alpha1.IntentEndpoint, 0, len(ports))
+
+	for index, port := range ports {
+		switch {
+		case hosts[index] != "":
+			endpoints = append(endpoints, flarev1alpha1.IntentEndpoint{Port: port.Port, URL: scheme + "://" + hosts[index]})
+		case address != "":
+			// The rules with no host are reachable at the Ingress address once assigned, with no certificate.
+			endpoints = append(endpoints, flarev1alpha1.IntentEndpoint{Port: port.Port, URL: "http://" + address})
+		}
+	}
+
+	return endpoints, nil
+}
+
+// loadBalancerExposure exposes the ports through a LoadBalancer Service, at the address assigned to it:
+// no endpoint is returned until the address is assigned.
+type loadBalancerExposure struct {
+	*IntentReconciler
+}
+
+func (i *loadBalancerExposure) expose(ctx context.Context, intent *flarev1alpha1.Intent, ports []flarev1alpha1.IntentWorkloadPort) ([]flarev1alpha1.IntentEndpoint, error) {
+	var svc corev1.Service
+	svc.Name = intent.Namespace + "-exposed"
+	svc.Namespace = intent.Namespace
+
+	if len(ports) == 0 {
+		if err := i.Client.Delete(ctx, &svc); err != nil && !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+
+	_, err := controllerutil.CreateOrUpdate(ctx, i.Client, &svc, func() error {
+		svc.Spec.Type = corev1.ServiceTypeLoadBalancer
+		svc.Spec.Selector = map[string]string{
+			"intent": intent.Name,
+		}
+
+		if len(svc.Spec.Ports) != len(ports) {
+			svc.Spec.Ports = make([]corev1.ServicePort, len(ports))
+		}
+
+		for k, port := range ports {
+			svc.Spec.Ports[k].Name = strings.ToLower(port.Protocol) + "-" + strconv.FormatInt(int64(port.Port), 10)
+			svc.Spec.Ports[k].Protocol = corev1.Protocol(port.Protocol)
+			svc.Spec.Ports[k].Port = port.Port
+			svc.Spec.Ports[k].TargetPort = intstr.FromInt32(port.Port)
+		}
+
+		return controllerutil.SetOwnerReference(intent, &svc, i.Client.Scheme())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var address string
+	for _, ingress := range svc.Status.LoadBalancer.Ingress {
+		if address = cmp.Or(ingress.IP, ingress.Hostname); address != "" {
+			break
+		}
+	}
+
+	if address == "" {
+		return nil, nil
+	}
+
+	endpoints := make([]flarev1alpha1.IntentEndpoint, 0, len(ports))
+	for _, port := range ports {
+		endpoints = append(endpoints, flarev1alpha1.IntentEndpoint{
+			Port: port.Port,
+			URL:  portScheme(port) + "://" + net.JoinHostPort(address, strconv.FormatInt(int64(port.Port), 10)),
+		})
+	}
+
+	return endpoints, nil
+}
+
+var (
+	gatewayGVK   = schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "Gateway"}
+	httpRouteGVK = schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "HTTPRoute"}
+	tcpRouteGVK  = schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1alpha2", Kind: "TCPRoute"}
+	udpRouteGVK  = schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1alpha2", Kind: "UDPRoute"}
+)
+
+// gatewayExposure exposes the ports through the Gateway API routes attached to the configured Gateway, a route each:
+// HTTP ports are routed upon their domain, the other ones upon the Gateway listener with the same port.
+// The Gateway API objects are handled as unstructured ones, since TCP and UDP routes are still experimental.
+type gatewayExposure struct {
+	*IntentReconciler
+}
+
+//+kubebuilder:rbac:groups="gateway.networking.k8s.io",resources=gateways,verbs=get;list;watch
+//+kubebuilder:rbac:groups="gateway.networking.k8s.io",resources=httproutes;tcproutes;udproutes,verbs=create;get;list;watch;update;delete
+
+func (i *gatewayExposure) expose(ctx context.Context, intent *flarev1alpha1.Intent, ports []flarev1alpha1.IntentWorkloadPort) ([]flarev1alpha1.IntentEndpoint, error) {
+	namespace, name, found := strings.Cut(i.Gateway, "/")
+	if !found {
+		return nil, fmt.Errorf("gateway %q must be in the form <namespace>/<name>", i.Gateway)
+	}
+
+	gateway := &unstructured.Unstructured{}
+	gateway.SetGroupVersionKind(gatewayGVK)
+
+	if err := i.Client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, gateway); err != nil {
+		return nil, errors.Wrap(err, "cannot retrieve Gateway "+i.Gateway)
+	}
+
+	scheme := "http"
+
+	listeners, _, _ := unstructured.NestedSlice(gateway.Object, "spec", "listeners")
+	for _, listener := range listeners {
+		if protocol, _, _ := unstructured.NestedString(listener.(map[string]interface{}), "protocol"); protocol == "HTTPS" {
+			scheme = "https"
+		}
+	}
+
+	var address string
+	if addresses, _, _ := unstructured.NestedSlice(gateway.Object, "status", "addresses"); len(addresses) > 0 {
+		address, _, _ = unstructured.NestedString(addresses[0].(map[string]interface{}), "value")
+	}
+
+	desired := map[schema.GroupVersionKind][]string{}
+	endpoints := make([]flarev1alpha1.IntentEndpoint, 0, len(ports))
+
+	for _, port := range ports {
+		route := &unstructured.Unstructured{}
+		route.SetName(fmt.Sprintf("%s-%d", intent.Namespace, port.Port))
+		route.SetNamespace(intent.Namespace)
+
+		parentRef := map[string]interface{}{
+			"name":      name,
+			"namespace": namespace,
+		}
+
+		spec := map[string]interface{}{
+			"rules": []interface{}{
+				map[string]interface{}{
+					"backendRefs": []interface{}{
+						map[string]interface{}{
+							"name": intent.Namespace,
+							"port": int64(port.Port),
+						},
+					},
+				},
+			},
+		}
+
+		switch {
+		case httpPort(port):
+			route.SetGroupVersionKind(httpRouteGVK)
+
+			// The route with no hostnames matches any host, reachable at the Gateway address.
+			switch host := i.exposedHost(intent, port); {
+			case host != "":
+				spec["hostnames"] = []interface{}{host}
+
+				endpoints = append(endpoints, flarev1alpha1.IntentEndpoint{Port: port.Port, URL: scheme + "://" + host})
+			case address != "":
+				endpoints = append(endpoints, flarev1alpha1.IntentEndpoint{Port: port.Port, URL: scheme + "://" + address})
+			}
+		default:
+			route.SetGroupVersionKind(tcpRouteGVK)
+			if port.Protocol == "UDP" {
+				route.SetGroupVersionKind(udpRouteGVK)
+			}
+			// Attaching to the listener with the same port, the one the endpoint is reachable at.
+			parentRef["port"] = int64(port.Port)
+
+			if address != "" {
+				endpoints = append(endpoints, flarev1alpha1.IntentEndpoint{
+					Port: port.Port,
+					URL:  portScheme(port) + "://" + net.JoinHostPort(address, strconv.FormatInt(int64(port.Port), 10)),
+				})
+			}
+		}
+
+		spec["parentRefs"] = []interface{}{parentRef}
+
+		_, err := controllerutil.CreateOrUpdate(ctx, i.Client, route, func() error {
+			labels := route.GetLabels()
+			if labels == nil {
+				labels = map[string]string{}
+			}
+
+			labels["intent"] = intent.Name
+			route.SetLabels(labels)
+
+			route.Object["spec"] = spec
+
+			return controllerutil.SetOwnerReference(intent, route, i.Client.Scheme())
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot create "+route.GetKind()+" "+route.GetName())
+		}
+
+		desired[route.GroupVersionKind()] = append(desired[route.GroupVersionKind()], route.GetName())
+	}
+	// Removing the routes of the ports not exposed anymore, or whose protocol has changed.
+	for _, gvk := range []schema.GroupVersionKind{httpRouteGVK, tcpRouteGVK, udpRouteGVK} {
+		routeList := &unstructured.UnstructuredList{}
+		routeList.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+
+		if err := i.Client.List(ctx, routeList, client.InNamespace(intent.Namespace), client.MatchingLabels{"intent": intent.Name}); err != nil {
+			if meta.IsNoMatchError(err) {
+				continue
+			}
+
+			return nil, errors.Wrap(err, "cannot list "+gvk.Kind)
+		}
+
+		for _, route := range routeList.Items {
+			if slices.Contains(desired[gvk], route.GetName()) {
+				continue
+			}
+
+			if err := i.Client.Delete(ctx, &route); err != nil && !apierrors.IsNotFound(err) {
+				return nil, errors.Wrap(err, "cannot delete "+gvk.Kind+" "+route.GetName())
+			}
+		}
+	}
+
+	return endpoints, nil
+}
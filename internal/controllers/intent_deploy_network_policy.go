@@ -0,0 +1,371 @@
+// Copyright 2025 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	flarev1alpha1 "github.com/clastix/flare-internal/api/v1alpha1"
+)
+
+const (
+	networkPolicyDefaultDenySuffix = "-default-deny"
+	networkPolicyFirewallSuffix    = "-firewall"
+)
+
+type firewallPort struct {
+	protocol corev1.Protocol
+	port     int32
+}
+
+// HandleNetworkPolicies materializes the security constraints of the Intent as NetworkPolicy objects.
+// A Private Intent gets a default-deny policy allowing only the traffic from the same Namespace,
+// and the Allow firewall rules are rendered as ingress rules on top of it.
+// A Public Intent allows any ingress traffic, thus only the Deny firewall rules are rendered,
+// expressed as ipBlock exceptions of the otherwise allowed traffic.
+// Rules which cannot be translated are reported in the NetworkPolicy condition.
+// The offloaded Pods run on the provider cluster, where Liqo doesn't reflect NetworkPolicy objects:
+// the policies are rendered in the Intent Namespace, but reported as not enforced.
+func (i *IntentReconciler) HandleNetworkPolicies(ctx context.Context, intent *flarev1alpha1.Intent) error {
+	condition := meta.FindStatusCondition(intent.Status.Conditions, flarev1alpha1.IntentStatusTypeNetworkPolicy)
+	if condition == nil {
+		condition = &metav1.Condition{Type: flarev1alpha1.IntentStatusTypeNetworkPolicy}
+	} else {
+		condition = condition.DeepCopy()
+	}
+
+	condition.Status = metav1.ConditionTrue
+	condition.Reason = "NetworkPoliciesHandled"
+	condition.Message = ""
+
+	private := intent.Spec.Constraints.Security.NetworkIsolation == "Private"
+
+	rules, untranslated := i.kubernetesFirewallIngressRules(intent, private)
+
+	if err := i.kubernetesDefaultDenyNetworkPolicy(ctx, intent, private); err != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "DefaultDenyNetworkPolicyFailed"
+		condition.Message = err.Error()
+
+		return UpdateStatusCondition(ctx, i.Client, intent, *condition)
+	}
+
+	if err := i.kubernetesFirewallNetworkPolicy(ctx, intent, rules); err != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "FirewallNetworkPolicyFailed"
+		condition.Message = err.Error()
+
+		return UpdateStatusCondition(ctx, i.Client, intent, *condition)
+	}
+
+	switch {
+	case len(untranslated) > 0:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "FirewallRulesNotTranslated"
+		condition.Message = strings.Join(untranslated, "; ")
+	case private || len(rules) > 0:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "NetworkPoliciesNotEnforced"
+		condition.Message = "NetworkPolicies are not reflected to the provider cluster running the offloaded Pods"
+	}
+
+	return UpdateStatusCondition(ctx, i.Client, intent, *condition)
+}
+
+//+kubebuilder:rbac:groups="networking.k8s.io",resources=networkpolicies,verbs=create;get;list;watch;update;delete
+
+func (i *IntentReconciler) kubernetesDefaultDenyNetworkPolicy(ctx context.Context, intent *flarev1alpha1.Intent, private bool) error {
+	var policy networkingv1.NetworkPolicy
+	policy.Name = intent.Namespace + networkPolicyDefaultDenySuffix
+	policy.Namespace = intent.Namespace
+
+	if !private {
+		if err := i.Client.Delete(ctx, &policy); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+
+		return nil
+	}
+
+	_, err := controllerutil.CreateOrUpdate(ctx, i.Client, &policy, func() error {
+		policy.Spec.PodSelector = metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				"intent": intent.Name,
+			},
+		}
+		policy.Spec.PolicyTypes = []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}
+		policy.Spec.Ingress = []networkingv1.NetworkPolicyIngressRule{
+			{
+				From: []networkingv1.NetworkPolicyPeer{
+					{
+						PodSelector: &metav1.LabelSelector{},
+					},
+				},
+			},
+		}
+
+		return controllerutil.SetOwnerReference(intent, &policy, i.Client.Scheme())
+	})
+
+	return err
+}
+
+func (i *IntentReconciler) kubernetesFirewallNetworkPolicy(ctx context.Context, intent *flarev1alpha1.Intent, rules []networkingv1.NetworkPolicyIngressRule) error {
+	var policy networkingv1.NetworkPolicy
+	policy.Name = intent.Namespace + networkPolicyFirewallSuffix
+	policy.Namespace = intent.Namespace
+
+	if len(rules) == 0 {
+		if err := i.Client.Delete(ctx, &policy); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+
+		return nil
+	}
+
+	_, err := controllerutil.CreateOrUpdate(ctx, i.Client, &policy, func() error {
+		policy.Spec.PodSelector = metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				"intent": intent.Name,
+			},
+		}
+		policy.Spec.PolicyTypes = []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}
+		policy.Spec.Ingress = rules
+
+		return controllerutil.SetOwnerReference(intent, &policy, i.Client.Scheme())
+	})
+
+	return err
+}
+
+// kubernetesFirewallIngressRules translates the firewall rules into NetworkPolicy ingress rules,
+// returning the description of the ones that cannot be expressed.
+func (i *IntentReconciler) kubernetesFirewallIngressRules(intent *flarev1alpha1.Intent, private bool) ([]networkingv1.NetworkPolicyIngressRule, []string) {
+	type allowRule struct {
+		port   firewallPort
+		cidr   *net.IPNet
+		except []string
+	}
+
+	var untranslated []string
+
+	allows := make([]*allowRule, 0, len(intent.Spec.Constraints.Security.FirewallRules))
+	denies := make(map[firewallPort][]*net.IPNet)
+
+	for _, rule := range intent.Spec.Constraints.Security.FirewallRules {
+		description := fmt.Sprintf("%s %s/%d from %s", rule.Action, rule.Protocol, rule.Port, rule.Source)
+
+		protocol := corev1.Protocol(strings.ToUpper(rule.Protocol))
+		if protocol != corev1.ProtocolTCP && protocol != corev1.ProtocolUDP && protocol != corev1.ProtocolSCTP {
+			untranslated = append(untranslated, description+": unsupported protocol")
+
+			continue
+		}
+
+		if rule.Port < 1 || rule.Port > 65535 {
+			untranslated = append(untranslated, description+": port out of range")
+
+			continue
+		}
+
+		_, cidr, err := net.ParseCIDR(rule.Source)
+		if err != nil {
+			untranslated = append(untranslated, description+": source is not a valid CIDR")
+
+			continue
+		}
+
+		port := firewallPort{protocol: protocol, port: rule.Port}
+
+		switch rule.Action {
+		case "Allow":
+			allows = append(allows, &allowRule{port: port, cidr: cidr})
+		case "Deny":
+			denies[port] = append(denies[port], cidr)
+		default:
+			untranslated = append(untranslated, description+": unsupported action")
+		}
+	}
+
+	rules := make([]networkingv1.NetworkPolicyIngressRule, 0, len(allows)+len(denies))
+
+	if !private {
+		// Any ingress traffic is allowed, unless a Deny rule targets the given port:
+		// the ports not subject to Deny rules are allowed from everywhere,
+		// the remaining ones from everywhere except the denied CIDRs.
+		if len(denies) == 0 {
+			return nil, untranslated
+		}
+
+		rules = append(rules, networkingv1.NetworkPolicyIngressRule{
+			Ports: networkPolicyPortsExcluding(denies),
+		})
+
+		for _, port := range sortedFirewallPorts(denies) {
+			rule := networkingv1.NetworkPolicyIngressRule{
+				Ports: []networkingv1.NetworkPolicyPort{networkPolicyPort(port)},
+			}
+
+			for _, everywhere := range []string{"0.0.0.0/0", "::/0"} {
+				_, all, _ := net.ParseCIDR(everywhere)
+
+				block := networkingv1.IPBlock{CIDR: everywhere}
+
+				for _, cidr := range denies[port] {
+					switch {
+					case !sameIPFamily(all, cidr):
+						continue
+					case cidrEqual(all, cidr):
+						block.CIDR = ""
+					case block.CIDR != "":
+						block.Except = append(block.Except, cidr.String())
+					}
+				}
+
+				if block.CIDR == "" {
+					continue
+				}
+
+				rule.From = append(rule.From, networkingv1.NetworkPolicyPeer{IPBlock: block.DeepCopy()})
+			}
+
+			if len(rule.From) > 0 {
+				rules = append(rules, rule)
+			}
+		}
+
+		return rules, untranslated
+	}
+
+	// The default-deny policy already rejects everything not explicitly allowed:
+	// Deny rules are relevant only when overlapping an Allow one for the same port.
+	for _, port := range sortedFirewallPorts(denies) {
+		for _, cidr := range denies[port] {
+			for _, allow := range allows {
+				if allow.cidr == nil || allow.port != port || !sameIPFamily(allow.cidr, cidr) {
+					continue
+				}
+
+				switch {
+				case cidrContains(cidr, allow.cidr):
+					// The whole allowed range is denied, thus dropping the Allow rule.
+					allow.cidr = nil
+				case cidrContains(allow.cidr, cidr):
+					allow.except = append(allow.except, cidr.String())
+				case allow.cidr.Contains(cidr.IP) || cidr.Contains(allow.cidr.IP):
+					untranslated = append(untranslated, fmt.Sprintf("Deny %s/%d from %s: partially overlapping with allowed %s", port.protocol, port.port, cidr.String(), allow.cidr.String()))
+				}
+			}
+		}
+	}
+
+	for _, allow := range allows {
+		if allow.cidr == nil {
+			continue
+		}
+
+		rules = append(rules, networkingv1.NetworkPolicyIngressRule{
+			Ports: []networkingv1.NetworkPolicyPort{networkPolicyPort(allow.port)},
+			From: []networkingv1.NetworkPolicyPeer{
+				{
+					IPBlock: &networkingv1.IPBlock{
+						CIDR:   allow.cidr.String(),
+						Except: allow.except,
+					},
+				},
+			},
+		})
+	}
+
+	return rules, untranslated
+}
+
+func networkPolicyPort(port firewallPort) networkingv1.NetworkPolicyPort {
+	return networkingv1.NetworkPolicyPort{
+		Protocol: ptr.To(port.protocol),
+		Port:     ptr.To(intstr.FromInt32(port.port)),
+	}
+}
+
+// networkPolicyPortsExcluding returns the port ranges for all the supported protocols,
+// excluding the provided ones.
+func networkPolicyPortsExcluding(excluded map[firewallPort][]*net.IPNet) []networkingv1.NetworkPolicyPort {
+	var out []networkingv1.NetworkPolicyPort
+
+	for _, protocol := range []corev1.Protocol{corev1.ProtocolTCP, corev1.ProtocolUDP, corev1.ProtocolSCTP} {
+		start := int32(1)
+
+		for _, port := range sortedFirewallPorts(excluded) {
+			if port.protocol != protocol {
+				continue
+			}
+
+			if port.port > start {
+				out = append(out, networkingv1.NetworkPolicyPort{
+					Protocol: ptr.To(protocol),
+					Port:     ptr.To(intstr.FromInt32(start)),
+					EndPort:  ptr.To(port.port - 1),
+				})
+			}
+
+			start = port.port + 1
+		}
+
+		if start <= 65535 {
+			out = append(out, networkingv1.NetworkPolicyPort{
+				Protocol: ptr.To(protocol),
+				Port:     ptr.To(intstr.FromInt32(start)),
+				EndPort:  ptr.To(int32(65535)),
+			})
+		}
+	}
+
+	return out
+}
+
+func sortedFirewallPorts(ports map[firewallPort][]*net.IPNet) []firewallPort {
+	out := make([]firewallPort, 0, len(ports))
+	for port := range ports {
+		out = append(out, port)
+	}
+
+	slices.SortFunc(out, func(a, b firewallPort) int {
+		if a.protocol != b.protocol {
+			return strings.Compare(string(a.protocol), string(b.protocol))
+		}
+
+		return int(a.port - b.port)
+	})
+
+	return out
+}
+
+func sameIPFamily(a, b *net.IPNet) bool {
+	return (a.IP.To4() == nil) == (b.IP.To4() == nil)
+}
+
+// cidrContains reports whether the inner network is fully contained in the outer one.
+func cidrContains(outer, inner *net.IPNet) bool {
+	outerOnes, _ := outer.Mask.Size()
+	innerOnes, _ := inner.Mask.Size()
+
+	return outerOnes <= innerOnes && outer.Contains(inner.IP)
+}
+
+func cidrEqual(a, b *net.IPNet) bool {
+	return cidrContains(a, b) && cidrContains(b, a)
+}
@@ -0,0 +1,195 @@
+// Copyright 2025 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+
+	flarev1alpha1 "github.com/clastix/flare-internal/api/v1alpha1"
+)
+
+func TestKubernetesFirewallIngressRules(t *testing.T) {
+	rule := func(action, protocol string, port int32, source string) flarev1alpha1.IntentWorkloadConstraintSecurityFirewallRule {
+		return flarev1alpha1.IntentWorkloadConstraintSecurityFirewallRule{
+			Action:   action,
+			Protocol: protocol,
+			Port:     port,
+			Source:   source,
+		}
+	}
+
+	port := func(protocol corev1.Protocol, start, end int32) networkingv1.NetworkPolicyPort {
+		out := networkingv1.NetworkPolicyPort{
+			Protocol: ptr.To(protocol),
+			Port:     ptr.To(intstr.FromInt32(start)),
+		}
+
+		if end != 0 {
+			out.EndPort = ptr.To(end)
+		}
+
+		return out
+	}
+
+	from := func(cidr string, except ...string) networkingv1.NetworkPolicyPeer {
+		return networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: cidr, Except: except}}
+	}
+
+	tests := []struct {
+		name         string
+		private      bool
+		rules        []flarev1alpha1.IntentWorkloadConstraintSecurityFirewallRule
+		want         []networkingv1.NetworkPolicyIngressRule
+		untranslated int
+	}{
+		{
+			name: "public without rules",
+		},
+		{
+			name:  "public ignoring allow rules",
+			rules: []flarev1alpha1.IntentWorkloadConstraintSecurityFirewallRule{rule("Allow", "TCP", 443, "10.0.0.0/8")},
+		},
+		{
+			name:  "public denying a CIDR",
+			rules: []flarev1alpha1.IntentWorkloadConstraintSecurityFirewallRule{rule("Deny", "TCP", 22, "10.0.0.0/8")},
+			want: []networkingv1.NetworkPolicyIngressRule{
+				{
+					Ports: []networkingv1.NetworkPolicyPort{
+						port(corev1.ProtocolTCP, 1, 21),
+						port(corev1.ProtocolTCP, 23, 65535),
+						port(corev1.ProtocolUDP, 1, 65535),
+						port(corev1.ProtocolSCTP, 1, 65535),
+					},
+				},
+				{
+					Ports: []networkingv1.NetworkPolicyPort{port(corev1.ProtocolTCP, 22, 0)},
+					From:  []networkingv1.NetworkPolicyPeer{from("0.0.0.0/0", "10.0.0.0/8"), from("::/0")},
+				},
+			},
+		},
+		{
+			name:  "public denying the whole IPv4 range",
+			rules: []flarev1alpha1.IntentWorkloadConstraintSecurityFirewallRule{rule("Deny", "UDP", 53, "0.0.0.0/0")},
+			want: []networkingv1.NetworkPolicyIngressRule{
+				{
+					Ports: []networkingv1.NetworkPolicyPort{
+						port(corev1.ProtocolTCP, 1, 65535),
+						port(corev1.ProtocolUDP, 1, 52),
+						port(corev1.ProtocolUDP, 54, 65535),
+						port(corev1.ProtocolSCTP, 1, 65535),
+					},
+				},
+				{
+					Ports: []networkingv1.NetworkPolicyPort{port(corev1.ProtocolUDP, 53, 0)},
+					From:  []networkingv1.NetworkPolicyPeer{from("::/0")},
+				},
+			},
+		},
+		{
+			name: "public denying everywhere",
+			rules: []flarev1alpha1.IntentWorkloadConstraintSecurityFirewallRule{
+				rule("Deny", "TCP", 1, "0.0.0.0/0"),
+				rule("Deny", "TCP", 1, "::/0"),
+			},
+			want: []networkingv1.NetworkPolicyIngressRule{
+				{
+					Ports: []networkingv1.NetworkPolicyPort{
+						port(corev1.ProtocolTCP, 2, 65535),
+						port(corev1.ProtocolUDP, 1, 65535),
+						port(corev1.ProtocolSCTP, 1, 65535),
+					},
+				},
+			},
+		},
+		{
+			name:    "private without rules",
+			private: true,
+		},
+		{
+			name:    "private allowing a CIDR",
+			private: true,
+			rules:   []flarev1alpha1.IntentWorkloadConstraintSecurityFirewallRule{rule("Allow", "tcp", 443, "10.0.0.0/8")},
+			want: []networkingv1.NetworkPolicyIngressRule{
+				{
+					Ports: []networkingv1.NetworkPolicyPort{port(corev1.ProtocolTCP, 443, 0)},
+					From:  []networkingv1.NetworkPolicyPeer{from("10.0.0.0/8")},
+				},
+			},
+		},
+		{
+			name:    "private denying a subset of the allowed CIDR",
+			private: true,
+			rules: []flarev1alpha1.IntentWorkloadConstraintSecurityFirewallRule{
+				rule("Allow", "TCP", 443, "10.0.0.0/8"),
+				rule("Deny", "TCP", 443, "10.1.0.0/16"),
+			},
+			want: []networkingv1.NetworkPolicyIngressRule{
+				{
+					Ports: []networkingv1.NetworkPolicyPort{port(corev1.ProtocolTCP, 443, 0)},
+					From:  []networkingv1.NetworkPolicyPeer{from("10.0.0.0/8", "10.1.0.0/16")},
+				},
+			},
+		},
+		{
+			name:    "private denying the whole allowed CIDR",
+			private: true,
+			rules: []flarev1alpha1.IntentWorkloadConstraintSecurityFirewallRule{
+				rule("Allow", "TCP", 443, "10.1.0.0/16"),
+				rule("Deny", "TCP", 443, "10.0.0.0/8"),
+			},
+		},
+		{
+			name:    "private denying another port or IP family",
+			private: true,
+			rules: []flarev1alpha1.IntentWorkloadConstraintSecurityFirewallRule{
+				rule("Allow", "TCP", 443, "10.0.0.0/8"),
+				rule("Deny", "TCP", 80, "10.0.0.0/8"),
+				rule("Deny", "TCP", 443, "::/0"),
+			},
+			want: []networkingv1.NetworkPolicyIngressRule{
+				{
+					Ports: []networkingv1.NetworkPolicyPort{port(corev1.ProtocolTCP, 443, 0)},
+					From:  []networkingv1.NetworkPolicyPeer{from("10.0.0.0/8")},
+				},
+			},
+		},
+		{
+			name:    "untranslated rules",
+			private: true,
+			rules: []flarev1alpha1.IntentWorkloadConstraintSecurityFirewallRule{
+				rule("Allow", "ICMP", 1, "10.0.0.0/8"),
+				rule("Allow", "TCP", 0, "10.0.0.0/8"),
+				rule("Allow", "TCP", 443, "10.0.0.1"),
+				rule("Reject", "TCP", 443, "10.0.0.0/8"),
+			},
+			untranslated: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var intent flarev1alpha1.Intent
+			intent.Spec.Constraints.Security.FirewallRules = tt.rules
+
+			rules, untranslated := (&IntentReconciler{}).kubernetesFirewallIngressRules(&intent, tt.private)
+			if len(untranslated) != tt.untranslated {
+				t.Fatalf("kubernetesFirewallIngressRules() untranslated = %q, expected %d", untranslated, tt.untranslated)
+			}
+
+			if len(rules) == 0 && len(tt.want) == 0 {
+				return
+			}
+
+			if !reflect.DeepEqual(rules, tt.want) {
+				t.Errorf("kubernetesFirewallIngressRules() = %+v, expected %+v", rules, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2025 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	flarev1alpha1 "github.com/clastix/flare-internal/api/v1alpha1"
+)
+
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=create;get;list;watch;update;delete
+
+// kubernetesSecret manages the Secret named after the Intent the workload secrets are injected from:
+// the values are copied from the Tenant secrets they reference, or from the ones supplied inline at the submission.
+func (i *IntentReconciler) kubernetesSecret(ctx context.Context, intent *flarev1alpha1.Intent) error {
+	var secret corev1.Secret
+	secret.Name = intent.Name
+	secret.Namespace = intent.Namespace
+
+	if len(intent.Spec.Workload.Secrets) == 0 {
+		if err := i.Client.Delete(ctx, &secret); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+
+		return nil
+	}
+
+	sources := map[types.NamespacedName]*corev1.Secret{}
+	data := make(map[string][]byte, len(intent.Spec.Workload.Secrets))
+
+	for _, ref := range intent.Spec.Workload.Secrets {
+		key := types.NamespacedName{Namespace: intent.Namespace, Name: flarev1alpha1.InlineSecretName(intent)}
+		field := ref.Name
+
+		if ref.From != "" {
+			key = types.NamespacedName{Namespace: "tenants", Name: flarev1alpha1.TenantSecretName(intentTenant(intent), ref.From)}
+
+			if ref.Key != "" {
+				field = ref.Key
+			}
+		}
+
+		source, ok := sources[key]
+		if !ok {
+			source = &corev1.Secret{}
+			if err := i.Client.Get(ctx, key, source); err != nil {
+				if apierrors.IsNotFound(err) {
+					return fmt.Errorf("secret %s is missing", ref.Name)
+				}
+
+				return errors.Wrap(err, "cannot retrieve the Secret of "+ref.Name)
+			}
+
+			sources[key] = source
+		}
+
+		value, ok := source.Data[field]
+		if !ok {
+			return fmt.Errorf("secret %s has no %s key", ref.Name, field)
+		}
+
+		data[ref.Name] = value
+	}
+
+	_, err := controllerutil.CreateOrUpdate(ctx, i.Client, &secret, func() error {
+		secret.Type = corev1.SecretTypeOpaque
+		secret.Data = data
+
+		return controllerutil.SetOwnerReference(intent, &secret, i.Client.Scheme())
+	})
+
+	return err
+}
@@ -0,0 +1,148 @@
+// Copyright 2025 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	fluidosv1alpha1 "github.com/fluidos-project/node/apis/advertisement/v1alpha1"
+	fluidosnodev1alpha1 "github.com/fluidos-project/node/apis/nodecore/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	flarev1alpha1 "github.com/clastix/flare-internal/api/v1alpha1"
+	"github.com/clastix/flare-internal/internal/flavor"
+)
+
+const (
+	negotiationFallbackFail   = "fail"
+	negotiationFallbackNotify = "notify"
+)
+
+// negotiationOutcome is the result of the negotiation rounds performed upon the PeeringCandidates.
+type negotiationOutcome struct {
+	// accepted are the offers within the bounds of the last round, empty when none has been accepted.
+	accepted []fluidosv1alpha1.PeeringCandidate
+	// rounds is the number of rounds performed.
+	rounds int
+	// closest is the offer with the lowest deviation from the Intent requirements.
+	closest fluidosv1alpha1.PeeringCandidate
+	// deviations maps the PeeringCandidates to their largest relative gap from the Intent requirements.
+	deviations map[string]float64
+}
+
+// offerDeviation returns the relative gaps of the Flavor from the Intent price, and resources requirements:
+// zero is a match, and the resources not advertised by the Flavor are trusted to the Solver selector.
+func offerDeviation(intent *flarev1alpha1.Intent, f fluidosnodev1alpha1.Flavor) (float64, float64) {
+	var price, resources float64
+
+	if maxHourlyCost := intent.Spec.Constraints.MaxHourlyCost; maxHourlyCost > 0 {
+		rate, ok := flavor.HourlyRate(f)
+		if !ok {
+			price = math.Inf(1)
+		} else {
+			price = max(0, rate/maxHourlyCost-1)
+		}
+	}
+
+	requirements := []struct {
+		required   resource.Quantity
+		advertised func(fluidosnodev1alpha1.Flavor) (resource.Quantity, bool)
+	}{
+		{required: intent.Spec.Workload.Resources.CPU, advertised: flavor.CPU},
+		{required: intent.Spec.Workload.Resources.Memory, advertised: flavor.Memory},
+	}
+
+	for _, requirement := range requirements {
+		if requirement.required.IsZero() {
+			continue
+		}
+
+		advertised, ok := requirement.advertised(f)
+		if !ok {
+			continue
+		}
+
+		resources = max(resources, 1-advertised.AsApproximateFloat64()/requirement.required.AsApproximateFloat64())
+	}
+
+	return price, resources
+}
+
+// negotiableMinimum returns the lowest quantity of the resource requirement accepted in the negotiation,
+// used as the Solver selector lower bound to discover the offers the flexibility applies to.
+func negotiableMinimum(intent *flarev1alpha1.Intent, required resource.Quantity) resource.Quantity {
+	negotiation := intent.Spec.Constraints.Negotiation
+
+	factor := 1 - negotiation.ResourceFlexibility - negotiation.AutoAcceptThreshold
+	if factor >= 1 {
+		return required
+	}
+
+	return *resource.NewMilliQuantity(int64(float64(required.MilliValue())*max(0, factor)), required.Format)
+}
+
+// negotiationRounds returns the number of rounds of the Intent negotiation, the first one allowing no flexibility.
+func negotiationRounds(intent *flarev1alpha1.Intent) int {
+	return max(1, intent.Spec.Constraints.Negotiation.MaxNegotiationRounds)
+}
+
+// negotiationTimeout returns the time a negotiation ended with no accepted offer is waited for before a new discovery.
+func negotiationTimeout(intent *flarev1alpha1.Intent) time.Duration {
+	return time.Duration(max(1, intent.Spec.Constraints.Negotiation.TimeoutSeconds)) * time.Second
+}
+
+// negotiate performs the negotiation rounds upon the PeeringCandidates satisfying the Intent constraints,
+// emitting an Event for each of them. The price and resources flexibility is relaxed evenly across the rounds,
+// starting from an exact match: offers exceeding the round bounds within the auto-accept threshold are accepted too.
+// The negotiation stops at the first round accepting any offer.
+func (i *IntentReconciler) negotiate(intent *flarev1alpha1.Intent, candidates []fluidosv1alpha1.PeeringCandidate) negotiationOutcome {
+	negotiation := intent.Spec.Constraints.Negotiation
+
+	type deviation struct {
+		price, resources float64
+	}
+
+	deviations := make(map[string]deviation, len(candidates))
+	outcome := negotiationOutcome{deviations: make(map[string]float64, len(candidates))}
+
+	for index, candidate := range candidates {
+		price, resources := offerDeviation(intent, candidate.Spec.Flavor)
+
+		deviations[candidate.Name] = deviation{price: price, resources: resources}
+		outcome.deviations[candidate.Name] = max(price, resources)
+
+		if index == 0 || outcome.deviations[candidate.Name] < outcome.deviations[outcome.closest.Name] {
+			outcome.closest = candidate
+		}
+	}
+
+	rounds := negotiationRounds(intent)
+
+	for round := 1; round <= rounds; round++ {
+		var relaxation float64
+		if rounds > 1 {
+			relaxation = float64(round-1) / float64(rounds-1)
+		}
+
+		priceBound := negotiation.PriceFlexibility*relaxation + negotiation.AutoAcceptThreshold
+		resourcesBound := negotiation.ResourceFlexibility*relaxation + negotiation.AutoAcceptThreshold
+
+		outcome.rounds = round
+		outcome.accepted = slices.DeleteFunc(slices.Clone(candidates), func(candidate fluidosv1alpha1.PeeringCandidate) bool {
+			return deviations[candidate.Name].price > priceBound || deviations[candidate.Name].resources > resourcesBound
+		})
+
+		i.Recorder.Eventf(intent, corev1.EventTypeNormal, "NegotiationRound", "Round %d/%d: %d of %d offers within %.0f%% price and %.0f%% resources deviation",
+			round, rounds, len(outcome.accepted), len(candidates), priceBound*100, resourcesBound*100)
+
+		if len(outcome.accepted) > 0 {
+			break
+		}
+	}
+
+	return outcome
+}
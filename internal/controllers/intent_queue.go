@@ -0,0 +1,265 @@
+// Copyright 2025 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	fluidosnodev1alpha1 "github.com/fluidos-project/node/apis/nodecore/v1alpha1"
+	"github.com/fluidos-project/node/pkg/utils/flags"
+	"github.com/pkg/errors"
+	capsulev1beta2 "github.com/projectcapsule/capsule/api/v1beta2"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	flarev1alpha1 "github.com/clastix/flare-internal/api/v1alpha1"
+)
+
+// queueRefreshInterval is the interval the queue position of a waiting Intent is refreshed at.
+const queueRefreshInterval = 30 * time.Second
+
+// enqueue puts the Intent in the queue waiting for provider capacity, scheduling the next discovery upon the backoff
+// doubling at each attempt: the discovery is not retried before the given delay.
+func (i *IntentReconciler) enqueue(status *flarev1alpha1.IntentStatus, delay time.Duration) time.Time {
+	now := time.Now()
+
+	if status.Queue == nil {
+		status.Queue = &flarev1alpha1.IntentQueue{Since: metav1.NewTime(now)}
+	}
+
+	backoff := i.QueueBackoff
+	for attempt := int32(0); attempt < status.Queue.Attempts && backoff < i.QueueMaxBackoff; attempt++ {
+		backoff *= 2
+	}
+
+	next := now.Add(max(delay, min(backoff, i.QueueMaxBackoff)))
+	status.Queue.NextAttemptTime = ptr.To(metav1.NewTime(next))
+
+	return next
+}
+
+// queueSelection tracks the outcome of the PeeringCandidate selection in the queue: when no capacity satisfies the Intent,
+// it's queued for a new discovery, not before the negotiation timeout when the negotiation has been queued.
+// Any other outcome leaves the queue, which would count it as a discovery in progress.
+func (i *IntentReconciler) queueSelection(intent *flarev1alpha1.Intent, status *flarev1alpha1.IntentStatus, selection *candidateSelection, condition *metav1.Condition) {
+	var delay time.Duration
+
+	switch selection.reason {
+	case "SolverFailed":
+	case flarev1alpha1.IntentNegotiationReasonQueued:
+		delay = negotiationTimeout(intent)
+	default:
+		status.Queue = nil
+
+		return
+	}
+
+	next := i.enqueue(status, delay)
+
+	condition.Reason = flarev1alpha1.IntentSolverReasonQueued
+	condition.Message = fmt.Sprintf("%s, queued for a new discovery at %s", selection.message, next.UTC().Format(time.RFC3339))
+}
+
+// queuedGeneration returns the Solver generation the queued discovery is retried for, the migrating one if any.
+func queuedGeneration(status flarev1alpha1.IntentStatus) int64 {
+	if status.Migration != nil {
+		return status.Migration.Generation
+	}
+
+	return status.Allocation.Generation
+}
+
+// queued returns true when the Intent is waiting in the queue for its next discovery.
+func queued(intent *flarev1alpha1.Intent) bool {
+	return intent.Status.Queue != nil && intent.Status.Queue.NextAttemptTime != nil
+}
+
+// queueRequeueAfter returns the time left before the queued Intent position is refreshed, or its discovery retried.
+func queueRequeueAfter(intent *flarev1alpha1.Intent) time.Duration {
+	if !queued(intent) {
+		return 0
+	}
+
+	return max(time.Second, min(queueRefreshInterval, time.Until(intent.Status.Queue.NextAttemptTime.Time)))
+}
+
+// intentTenant returns the name of the Capsule Tenant owning the Intent,
+// since its Namespace is generated from the Tenant name.
+func intentTenant(intent *flarev1alpha1.Intent) string {
+	return strings.TrimSuffix(intent.Namespace, "-"+intent.Name)
+}
+
+//+kubebuilder:rbac:groups=capsule.clastix.io,resources=tenants,verbs=get;list;watch
+
+// queuedIntents returns the Intents in the queue, sorted by the order they're granted a new discovery:
+// the Tenant priority comes first, then the fair share favouring the Tenants with fewer allocated Intents,
+// and finally the queueing time.
+func (i *IntentReconciler) queuedIntents(ctx context.Context) ([]flarev1alpha1.Intent, error) {
+	var intentList flarev1alpha1.IntentList
+	if err := i.Client.List(ctx, &intentList); err != nil {
+		return nil, errors.Wrap(err, "cannot list Intents")
+	}
+
+	allocations := map[string]int{}
+	priorities := map[string]int{}
+
+	var queue []flarev1alpha1.Intent
+
+	for _, intent := range intentList.Items {
+		tenant := intentTenant(&intent)
+
+		switch {
+		case intent.Status.Queue != nil:
+			queue = append(queue, intent)
+		case meta.IsStatusConditionTrue(intent.Status.Conditions, flarev1alpha1.IntentStatusTypeSolver):
+			allocations[tenant]++
+		}
+	}
+
+	for _, intent := range queue {
+		tenant := intentTenant(&intent)
+		if _, ok := priorities[tenant]; ok {
+			continue
+		}
+
+		var tnt capsulev1beta2.Tenant
+		if err := i.Client.Get(ctx, types.NamespacedName{Name: tenant}, &tnt); err != nil && !apierrors.IsNotFound(err) {
+			return nil, errors.Wrap(err, "cannot retrieve Tenant "+tenant)
+		}
+
+		priorities[tenant], _ = strconv.Atoi(tnt.Annotations[flarev1alpha1.TenantPriorityAnnotation])
+	}
+
+	slices.SortStableFunc(queue, func(a, b flarev1alpha1.Intent) int {
+		tenantA, tenantB := intentTenant(&a), intentTenant(&b)
+
+		return cmp.Or(
+			cmp.Compare(priorities[tenantB], priorities[tenantA]),
+			cmp.Compare(allocations[tenantA], allocations[tenantB]),
+			a.Status.Queue.Since.Time.Compare(b.Status.Queue.Since.Time),
+			strings.Compare(a.Namespace, b.Namespace),
+		)
+	})
+
+	return queue, nil
+}
+
+// HandleQueue refreshes the position of the queued Intent, retrying the discovery once its backoff has expired,
+// and no more than the allowed concurrent discoveries are in progress for the Intents ahead in the queue:
+// the Solver is deleted, and created again at the next reconciliation.
+// The discovery of a migration is retried in place of the running allocation one, reported by the Migration condition.
+func (i *IntentReconciler) HandleQueue(ctx context.Context, intent *flarev1alpha1.Intent) error {
+	queue, err := i.queuedIntents(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	index := slices.IndexFunc(queue, func(other flarev1alpha1.Intent) bool {
+		return other.UID == intent.UID
+	})
+	if index < 0 {
+		// The cache is not yet reporting the Intent as queued.
+		index = len(queue)
+	}
+
+	position, inProgress := index+1, 0
+
+	for current, other := range queue {
+		switch next := other.Status.Queue.NextAttemptTime; {
+		case current == index:
+			continue
+		case next == nil:
+			discovering, err := i.discoveryInProgress(ctx, &other)
+			if err != nil {
+				return err
+			}
+
+			if discovering {
+				inProgress++
+			}
+		case current < index && !now.Before(next.Time):
+			// Intents ahead in the queue with an expired backoff are going to retry their discovery first.
+			inProgress++
+		}
+	}
+
+	if now.Before(intent.Status.Queue.NextAttemptTime.Time) || inProgress >= max(i.QueueConcurrency, 1) {
+		if intent.Status.Queue.Position == position {
+			return nil
+		}
+
+		return UpdateStatus(ctx, i.Client, intent, func(status *flarev1alpha1.IntentStatus) {
+			if status.Queue != nil {
+				status.Queue.Position = position
+			}
+		})
+	}
+
+	var solver fluidosnodev1alpha1.Solver
+	solver.Name = SolverName(intent, queuedGeneration(intent.Status))
+	solver.Namespace = flags.FluidosNamespace
+
+	if err = i.Client.Delete(ctx, &solver); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrap(err, "cannot delete the queued Solver")
+	}
+
+	conditionType := flarev1alpha1.IntentStatusTypeSolver
+	if intent.Status.Migration != nil {
+		conditionType = flarev1alpha1.IntentStatusTypeMigration
+	}
+
+	condition := metav1.Condition{
+		Type:    conditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  "SolverRequeued",
+		Message: fmt.Sprintf("Solver %s has been deleted for a new discovery, attempt %d", solver.Name, intent.Status.Queue.Attempts+1),
+	}
+
+	return UpdateStatus(ctx, i.Client, intent, func(status *flarev1alpha1.IntentStatus) {
+		meta.SetStatusCondition(&status.Conditions, condition)
+
+		if status.Migration != nil {
+			status.Migration.Negotiation = nil
+		} else {
+			status.Allocation.Negotiation = nil
+		}
+
+		if status.Queue != nil {
+			status.Queue.Position = position
+			status.Queue.Attempts++
+			status.Queue.LastAttemptTime = ptr.To(metav1.NewTime(now))
+			status.Queue.NextAttemptTime = nil
+		}
+	})
+}
+
+// discoveryInProgress returns true when the Solver retried for the queued Intent exists, and it's still discovering.
+func (i *IntentReconciler) discoveryInProgress(ctx context.Context, intent *flarev1alpha1.Intent) (bool, error) {
+	var solver fluidosnodev1alpha1.Solver
+	if err := i.Client.Get(ctx, types.NamespacedName{Name: SolverName(intent, queuedGeneration(intent.Status)), Namespace: flags.FluidosNamespace}, &solver); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+
+		return false, errors.Wrap(err, "cannot retrieve the queued Solver")
+	}
+
+	switch solver.Status.SolverPhase.Phase {
+	case fluidosnodev1alpha1.PhaseSolved, fluidosnodev1alpha1.PhaseFailed, fluidosnodev1alpha1.PhaseTimeout:
+		return false, nil
+	default:
+		return solver.DeletionTimestamp == nil, nil
+	}
+}
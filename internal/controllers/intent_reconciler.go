@@ -6,24 +6,69 @@ package controllers
 import (
 	"context"
 	"strings"
+	"time"
 
 	fluidosnodev1alpha1 "github.com/fluidos-project/node/apis/nodecore/v1alpha1"
 	"github.com/liqotech/liqo/apis/offloading/v1beta1"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	flarev1alpha1 "github.com/clastix/flare-internal/api/v1alpha1"
+	"github.com/clastix/flare-internal/internal/indexer"
+)
+
+const (
+	terminationRequeueAfter = 5 * time.Second
+	migrationRequeueAfter   = 10 * time.Second
 )
 
 type IntentReconciler struct {
 	Client client.Client
+	// Recorder emits the Intent Events, such as the negotiation rounds.
+	Recorder record.EventRecorder
+	// GPUUtilizationMetric is the Pods custom metric name used by the HorizontalPodAutoscaler
+	// to scale upon the GPU utilization target: when empty, GPU-based autoscaling is disabled.
+	GPUUtilizationMetric string
+	// GPUResourceName is the extended resource the workload container requests the GPUs through:
+	// when empty, the GPUs are not requested.
+	GPUResourceName string
+	// BudgetWarningThresholds are the percentages of the Intent maximum total cost
+	// reporting a warning in the Budget condition once crossed by the accrued cost.
+	BudgetWarningThresholds []int
+	// QueueBackoff is the delay of the first discovery retried for a queued Intent, doubling at each attempt
+	// up to QueueMaxBackoff.
+	QueueBackoff    time.Duration
+	QueueMaxBackoff time.Duration
+	// QueueConcurrency is the number of queued Intents allowed to retry their discovery at the same time, at least one.
+	QueueConcurrency int
+	// StorageClass provisions the persistent volumes with no source, an empty name standing for the cluster default.
+	StorageClass string
+	// SourceStorageClasses maps the volume source types to the StorageClasses of their CSI drivers.
+	SourceStorageClasses map[string]string
+	// IngressClass is the class of the Ingress exposing the workload ports, the cluster default one when empty.
+	IngressClass string
+	// IngressClusterIssuer is the cert-manager ClusterIssuer of the exposed ports TLS certificates:
+	// when empty, the ports are exposed over plain HTTP.
+	IngressClusterIssuer string
+	// IngressDomain is the wildcard domain the exposed ports with no custom domain are assigned a subdomain of.
+	IngressDomain string
+	// ExposureBackend is the backend exposing the workload ports, one of ingress, loadbalancer, or gateway.
+	ExposureBackend string
+	// Gateway is the Gateway the routes of the gateway exposure backend are attached to, in the form <namespace>/<name>.
+	Gateway string
 }
 
 //+kubebuilder:rbac:groups=flare.clastix.io,resources=intents,verbs=get;list;watch;create;update;patch;delete
@@ -46,7 +91,72 @@ func (i *IntentReconciler) Reconcile(ctx context.Context, request reconcile.Requ
 	}
 
 	if intent.DeletionTimestamp != nil {
-		logger.Info("skipping reconciliation for object marked for deletion")
+		logger.Info("handling Intent termination")
+
+		done, err := i.HandleTermination(ctx, &intent)
+		if err != nil {
+			logger.Error(err, "cannot handle Intent termination")
+
+			return reconcile.Result{}, err
+		}
+
+		if !done {
+			logger.Info("Intent termination is in progress")
+
+			return reconcile.Result{RequeueAfter: terminationRequeueAfter}, nil
+		}
+
+		logger.Info("Intent termination has been completed")
+
+		return reconcile.Result{}, nil
+	}
+
+	if updated, err := i.EnsureCleanupFinalizer(ctx, &intent); err != nil || updated {
+		if err != nil {
+			logger.Error(err, "cannot add cleanup finalizer")
+		}
+
+		return reconcile.Result{}, err
+	}
+
+	logger.Info("handling Intent deadline")
+
+	untilDeadline, admitted, err := i.HandleDeadline(ctx, &intent)
+	if err != nil {
+		logger.Error(err, "cannot handle Intent deadline")
+
+		return reconcile.Result{}, err
+	}
+
+	if !admitted {
+		logger.Info("Intent deadline has been exceeded, releasing allocations")
+		// The Intent is not admitted anymore: nothing is metered, thus the allocations must not be held.
+		var condition metav1.Condition
+
+		released, err := i.ReleaseAllocations(ctx, &intent, &condition)
+		if err != nil {
+			logger.Error(err, "cannot release Intent allocations")
+
+			return reconcile.Result{}, err
+		}
+
+		if !released {
+			logger.Info("Intent allocations release is in progress", "reason", condition.Reason, "message", condition.Message)
+
+			return reconcile.Result{RequeueAfter: terminationRequeueAfter}, nil
+		}
+		// The cost is not extrapolated anymore, since the released allocations are not metered.
+		if intent.Status.Cost.HourlyRate != 0 || intent.Status.Cost.LastMeteringTime != nil || intent.Status.Emissions.Rate != 0 {
+			if err = UpdateStatus(ctx, i.Client, &intent, func(status *flarev1alpha1.IntentStatus) {
+				status.Cost.HourlyRate = 0
+				status.Cost.LastMeteringTime = nil
+				status.Emissions.Rate = 0
+			}); err != nil {
+				logger.Error(err, "cannot reset Intent cost")
+
+				return reconcile.Result{}, err
+			}
+		}
 
 		return reconcile.Result{}, nil
 	}
@@ -66,7 +176,7 @@ func (i *IntentReconciler) Reconcile(ctx context.Context, request reconcile.Requ
 
 		logger.Info("Solver unknown phase has been completed")
 
-		return reconcile.Result{}, nil
+		return reconcile.Result{RequeueAfter: untilDeadline}, nil
 	case solverCondition.Status == metav1.ConditionUnknown:
 		logger.Info("creating Solver")
 
@@ -78,7 +188,7 @@ func (i *IntentReconciler) Reconcile(ctx context.Context, request reconcile.Requ
 
 		logger.Info("Solver creation has been completed")
 
-		return reconcile.Result{}, nil
+		return reconcile.Result{RequeueAfter: untilDeadline}, nil
 	case solverCondition.Status == metav1.ConditionFalse:
 		logger.Info("handling Solver")
 
@@ -90,7 +200,7 @@ func (i *IntentReconciler) Reconcile(ctx context.Context, request reconcile.Requ
 
 		logger.Info("Solver handling has been completed")
 
-		return reconcile.Result{}, nil
+		return reconcile.Result{RequeueAfter: earliest(queueRequeueAfter(&intent), untilDeadline)}, nil
 	case solverCondition.Status == metav1.ConditionTrue:
 		logger.Info("reconciling Solver")
 
@@ -118,7 +228,7 @@ func (i *IntentReconciler) Reconcile(ctx context.Context, request reconcile.Requ
 
 		logger.Info("NamespaceOffloading unknown phase has been completed")
 
-		return reconcile.Result{}, nil
+		return reconcile.Result{RequeueAfter: untilDeadline}, nil
 	case nsCondition.Status == metav1.ConditionUnknown:
 		logger.Info("creating NamespaceOffloading")
 
@@ -130,7 +240,7 @@ func (i *IntentReconciler) Reconcile(ctx context.Context, request reconcile.Requ
 
 		logger.Info("NamespaceOffloading creation has been completed")
 
-		return reconcile.Result{}, nil
+		return reconcile.Result{RequeueAfter: untilDeadline}, nil
 	case nsCondition.Status == metav1.ConditionFalse:
 		logger.Info("handling NamespaceOffloading")
 
@@ -142,7 +252,7 @@ func (i *IntentReconciler) Reconcile(ctx context.Context, request reconcile.Requ
 
 		logger.Info("NamespaceOffloading handling has been completed")
 
-		return reconcile.Result{}, nil
+		return reconcile.Result{RequeueAfter: untilDeadline}, nil
 	case nsCondition.Status == metav1.ConditionTrue:
 		logger.Info("reconciling NamespaceOffloading")
 
@@ -155,6 +265,39 @@ func (i *IntentReconciler) Reconcile(ctx context.Context, request reconcile.Requ
 		logger.Info("NamespaceOffloading reconciliation has been completed")
 	}
 
+	logger.Info("metering Intent cost")
+
+	if err := i.MeterCost(ctx, &intent); err != nil {
+		logger.Error(err, "cannot meter Intent cost")
+
+		return reconcile.Result{}, err
+	}
+
+	logger.Info("handling Intent budget")
+
+	if err := i.HandleBudget(ctx, &intent); err != nil {
+		logger.Error(err, "cannot handle Intent budget")
+
+		return reconcile.Result{}, err
+	}
+
+	logger.Info("handling Intent availability")
+
+	untilWindow, err := i.HandleAvailability(ctx, &intent)
+	if err != nil {
+		logger.Error(err, "cannot handle Intent availability")
+
+		return reconcile.Result{}, err
+	}
+
+	logger.Info("handling Intent storage")
+
+	if err := i.HandleStorage(ctx, &intent); err != nil {
+		logger.Error(err, "cannot handle Intent storage")
+
+		return reconcile.Result{}, err
+	}
+
 	logger.Info("handling resource deployment phase")
 
 	deployCondition := meta.FindStatusCondition(intent.Status.Conditions, flarev1alpha1.IntentStatusTypeDeploy)
@@ -170,7 +313,7 @@ func (i *IntentReconciler) Reconcile(ctx context.Context, request reconcile.Requ
 
 		logger.Info("Deploy unknown phase has been completed")
 
-		return reconcile.Result{}, nil
+		return reconcile.Result{RequeueAfter: untilDeadline}, nil
 	case deployCondition.Status == metav1.ConditionUnknown, deployCondition.Status == metav1.ConditionFalse:
 		logger.Info("creating Deploy")
 
@@ -182,7 +325,7 @@ func (i *IntentReconciler) Reconcile(ctx context.Context, request reconcile.Requ
 
 		logger.Info("Deploy creation has been completed")
 
-		return reconcile.Result{}, nil
+		return reconcile.Result{RequeueAfter: untilDeadline}, nil
 	case deployCondition.Status == metav1.ConditionTrue:
 		logger.Info("reconciling Deploy")
 
@@ -195,45 +338,108 @@ func (i *IntentReconciler) Reconcile(ctx context.Context, request reconcile.Requ
 		logger.Info("Deploy reconciliation has been completed")
 	}
 
+	logger.Info("handling Workload phase")
+
+	if err := i.HandleWorkloadPhase(ctx, &intent); err != nil {
+		logger.Error(err, "cannot handle Workload phase")
+
+		return reconcile.Result{}, err
+	}
+
+	logger.Info("Workload phase handling has been completed")
+
+	logger.Info("handling Intent migration")
+
+	migrated, err := i.HandleMigration(ctx, &intent)
+	if err != nil {
+		logger.Error(err, "cannot handle Intent migration")
+
+		return reconcile.Result{}, err
+	}
+
+	if !migrated {
+		logger.Info("Intent migration is in progress")
+
+		return reconcile.Result{RequeueAfter: earliest(migrationRequeueAfter, untilDeadline)}, nil
+	}
+
 	logger.Info("Intent has been reconciled")
 
-	return reconcile.Result{}, nil
+	var untilMetering time.Duration
+	// The budget is enforced upon the accrued cost, which is metered only upon reconciliation.
+	if intent.Spec.Constraints.MaxTotalCost > 0 {
+		untilMetering = costMeteringInterval
+	}
+	// The Job must be cancelled as soon as the deadline arrives,
+	// and the workload suspended or resumed as soon as the availability window closes or opens.
+	return reconcile.Result{RequeueAfter: earliest(untilMetering, untilDeadline, untilWindow)}, nil
+}
+
+// earliest returns the shortest of the given positive durations, zero when none is positive.
+func earliest(durations ...time.Duration) time.Duration {
+	var result time.Duration
+
+	for _, duration := range durations {
+		if duration > 0 && (result == 0 || duration < result) {
+			result = duration
+		}
+	}
+
+	return result
 }
 
 func (i *IntentReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// The custom domains are exposed only for the Intent claiming them first.
+	domainIndexer := indexer.IntentDomain{}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), domainIndexer.Object(), domainIndexer.Field(), domainIndexer.Func()); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&flarev1alpha1.Intent{}).
-		Watches(&v1beta1.NamespaceOffloading{}, handler.TypedEnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
-			parts := strings.Split(obj.GetNamespace(), "-")
-
-			if len(parts) != 2 {
+		Owns(&batchv1.Job{}, builder.MatchEveryOwner).
+		Owns(&appsv1.Deployment{}, builder.MatchEveryOwner).
+		Owns(&autoscalingv2.HorizontalPodAutoscaler{}, builder.MatchEveryOwner).
+		Owns(&corev1.PersistentVolumeClaim{}, builder.MatchEveryOwner).
+		Owns(&corev1.Service{}, builder.MatchEveryOwner).
+		Watches(&corev1.Secret{}, handler.TypedEnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+			// The values supplied inline with an update are stored once the Intent has been updated.
+			name, found := strings.CutSuffix(obj.GetName(), "-inline")
+			if !found {
 				return nil
 			}
 
-			return []reconcile.Request{
-				{
-					NamespacedName: types.NamespacedName{
-						Namespace: obj.GetNamespace(),
-						Name:      parts[1],
-					},
-				},
-			}
+			return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: name}}}
+		})).
+		Watches(&v1beta1.NamespaceOffloading{}, handler.TypedEnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+			return intentRequest(obj.GetNamespace())
 		})).
 		Watches(&fluidosnodev1alpha1.Solver{}, handler.TypedEnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
-			parts := strings.Split(obj.GetName(), "-")
-
-			if len(parts) != 2 {
+			solver, ok := obj.(*fluidosnodev1alpha1.Solver)
+			if !ok {
 				return nil
 			}
-
-			return []reconcile.Request{
-				{
-					NamespacedName: types.NamespacedName{
-						Namespace: obj.GetName(),
-						Name:      parts[1],
-					},
-				},
-			}
+			// Solvers of the following allocation generations are suffixed,
+			// the Intent Namespace is tracked as the Solver Intent ID.
+			return intentRequest(solver.Spec.IntentID)
 		})).
 		Complete(i)
 }
+
+// intentRequest maps the given Intent Namespace to the Intent reconciliation request:
+// the Namespace is generated from the Tenant name, the Intent is named after the generated suffix.
+func intentRequest(namespace string) []reconcile.Request {
+	index := strings.LastIndex(namespace, "-")
+	if index < 0 || index == len(namespace)-1 {
+		return nil
+	}
+
+	return []reconcile.Request{
+		{
+			NamespacedName: types.NamespacedName{
+				Namespace: namespace,
+				Name:      namespace[index+1:],
+			},
+		},
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright 2025 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	flarev1alpha1 "github.com/clastix/flare-internal/api/v1alpha1"
+)
+
+// persistentVolumes returns the workload volumes backed by a PersistentVolumeClaim.
+func persistentVolumes(intent *flarev1alpha1.Intent) []flarev1alpha1.IntentWorkloadStorageVolume {
+	return slices.DeleteFunc(slices.Clone(intent.Spec.Workload.Storage.Volumes), func(volume flarev1alpha1.IntentWorkloadStorageVolume) bool {
+		return volume.Type != "Persistent"
+	})
+}
+
+// storageClass returns the StorageClass provisioning the persistent volume upon its source type:
+// volumes with no source are provisioned with the default one, an empty name standing for the cluster default.
+func (i *IntentReconciler) storageClass(volume flarev1alpha1.IntentWorkloadStorageVolume) (string, bool) {
+	if volume.Source.Type == "" {
+		return i.StorageClass, true
+	}
+
+	class, ok := i.SourceStorageClasses[volume.Source.Type]
+
+	return class, ok
+}
+
+//+kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=create;get;list;watch;update;delete
+
+// HandleStorage provisions a PersistentVolumeClaim for each persistent volume of the workload, sized upon the volume,
+// and reports their binding in the Storage condition: the claims of the removed volumes are deleted.
+func (i *IntentReconciler) HandleStorage(ctx context.Context, intent *flarev1alpha1.Intent) error {
+	volumes := persistentVolumes(intent)
+
+	var pvcList corev1.PersistentVolumeClaimList
+	if err := i.Client.List(ctx, &pvcList, client.InNamespace(intent.Namespace), client.MatchingLabels{"intent": intent.Name}); err != nil {
+		return errors.Wrap(err, "cannot list PersistentVolumeClaims")
+	}
+
+	for _, pvc := range pvcList.Items {
+		if slices.ContainsFunc(volumes, func(volume flarev1alpha1.IntentWorkloadStorageVolume) bool {
+			return volume.Name == pvc.Name
+		}) {
+			continue
+		}
+
+		if err := i.Client.Delete(ctx, &pvc); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrap(err, "cannot delete PersistentVolumeClaim "+pvc.Name)
+		}
+	}
+
+	if len(volumes) == 0 {
+		if meta.FindStatusCondition(intent.Status.Conditions, flarev1alpha1.IntentStatusTypeStorage) == nil {
+			return nil
+		}
+
+		return UpdateStatus(ctx, i.Client, intent, func(status *flarev1alpha1.IntentStatus) {
+			meta.RemoveStatusCondition(&status.Conditions, flarev1alpha1.IntentStatusTypeStorage)
+		})
+	}
+
+	condition := metav1.Condition{
+		Type:               flarev1alpha1.IntentStatusTypeStorage,
+		ObservedGeneration: intent.Generation,
+		Status:             metav1.ConditionTrue,
+		Reason:             flarev1alpha1.IntentStorageReasonBound,
+		Message:            fmt.Sprintf("%d PersistentVolumeClaims are bound", len(volumes)),
+	}
+
+	var unsupported, pending, lost []string
+
+	for _, volume := range volumes {
+		class, ok := i.storageClass(volume)
+		if !ok {
+			unsupported = append(unsupported, volume.Name)
+
+			continue
+		}
+
+		pvc, err := i.kubernetesPersistentVolumeClaim(ctx, intent, volume, class)
+		if err != nil {
+			return err
+		}
+
+		switch pvc.Status.Phase {
+		case corev1.ClaimBound:
+		case corev1.ClaimLost:
+			lost = append(lost, pvc.Name)
+		default:
+			pending = append(pending, pvc.Name)
+		}
+	}
+
+	switch {
+	case len(unsupported) > 0:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = flarev1alpha1.IntentStorageReasonFailed
+		condition.Message = "no StorageClass is configured for the source type of volumes " + strings.Join(unsupported, ", ")
+	case len(lost) > 0:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = flarev1alpha1.IntentStorageReasonLost
+		condition.Message = "PersistentVolumes of claims " + strings.Join(lost, ", ") + " have been lost"
+	case len(pending) > 0:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = flarev1alpha1.IntentStorageReasonPending
+		condition.Message = "PersistentVolumeClaims " + strings.Join(pending, ", ") + " are waiting to be bound"
+	}
+
+	return UpdateStatusCondition(ctx, i.Client, intent, condition)
+}
+
+// kubernetesPersistentVolumeClaim manages the PersistentVolumeClaim of the persistent volume, named after it:
+// the volume source is exposed through annotations, for the StorageClass of its CSI driver to reference it.
+func (i *IntentReconciler) kubernetesPersistentVolumeClaim(ctx context.Context, intent *flarev1alpha1.Intent, volume flarev1alpha1.IntentWorkloadStorageVolume, class string) (*corev1.PersistentVolumeClaim, error) {
+	var credentials string
+
+	if volume.Source.Type != "" && volume.Source.Credentials != "" {
+		var err error
+		if credentials, err = i.kubernetesSourceCredentials(ctx, intent, volume); err != nil {
+			return nil, err
+		}
+	}
+
+	var pvc corev1.PersistentVolumeClaim
+	pvc.Name = volume.Name
+	pvc.Namespace = intent.Namespace
+
+	_, err := controllerutil.CreateOrUpdate(ctx, i.Client, &pvc, func() error {
+		if pvc.Labels == nil {
+			pvc.Labels = map[string]string{}
+		}
+
+		pvc.Labels["intent"] = intent.Name
+
+		if volume.Source.Type != "" {
+			if pvc.Annotations == nil {
+				pvc.Annotations = map[string]string{}
+			}
+
+			pvc.Annotations[flarev1alpha1.VolumeSourceURIAnnotation] = volume.Source.Uri
+
+			if credentials != "" {
+				pvc.Annotations[flarev1alpha1.VolumeSourceCredentialsAnnotation] = credentials
+			} else {
+				delete(pvc.Annotations, flarev1alpha1.VolumeSourceCredentialsAnnotation)
+			}
+		}
+		// The claim spec is immutable once created, but for the requested storage,
+		// which can only grow when the StorageClass allows the volume expansion.
+		if pvc.CreationTimestamp.IsZero() {
+			// Object storage sources are shared among the replicas, or the parallel tasks.
+			accessMode := corev1.ReadWriteOnce
+			if volume.Source.Type != "" {
+				accessMode = corev1.ReadWriteMany
+			}
+
+			pvc.Spec.AccessModes = []corev1.PersistentVolumeAccessMode{accessMode}
+			if class != "" {
+				pvc.Spec.StorageClassName = ptr.To(class)
+			}
+		}
+
+		if pvc.Spec.Resources.Requests == nil {
+			pvc.Spec.Resources.Requests = corev1.ResourceList{}
+		}
+
+		if requested := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; volume.Size.Cmp(requested) > 0 {
+			pvc.Spec.Resources.Requests[corev1.ResourceStorage] = volume.Size
+		}
+
+		return controllerutil.SetOwnerReference(intent, &pvc, i.Client.Scheme())
+	})
+
+	return &pvc, errors.Wrap(err, "cannot create PersistentVolumeClaim "+volume.Name)
+}
+
+// kubernetesSourceCredentials copies the Tenant secret storing the volume source credentials in the Intent Namespace,
+// returning the name of the copy the PersistentVolumeClaim references.
+func (i *IntentReconciler) kubernetesSourceCredentials(ctx context.Context, intent *flarev1alpha1.Intent, volume flarev1alpha1.IntentWorkloadStorageVolume) (string, error) {
+	var source corev1.Secret
+	if err := i.Client.Get(ctx, types.NamespacedName{Namespace: "tenants", Name: flarev1alpha1.TenantSecretName(intentTenant(intent), volume.Source.Credentials)}, &source); err != nil {
+		return "", errors.Wrap(err, "cannot retrieve the source credentials of volume "+volume.Name)
+	}
+
+	var secret corev1.Secret
+	secret.Name = volume.Name + "-source-credentials"
+	secret.Namespace = intent.Namespace
+
+	_, err := controllerutil.CreateOrUpdate(ctx, i.Client, &secret, func() error {
+		secret.Type = corev1.SecretTypeOpaque
+		secret.Data = source.Data
+
+		return controllerutil.SetOwnerReference(intent, &secret, i.Client.Scheme())
+	})
+
+	return secret.Name, errors.Wrap(err, "cannot create Secret "+secret.Name)
+}
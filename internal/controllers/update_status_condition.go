@@ -15,12 +15,19 @@ import (
 )
 
 func UpdateStatusCondition(ctx context.Context, clt client.Client, intent *flarev1alpha1.Intent, condition metav1.Condition) error {
+	return UpdateStatus(ctx, clt, intent, func(status *flarev1alpha1.IntentStatus) {
+		meta.SetStatusCondition(&status.Conditions, condition)
+	})
+}
+
+// UpdateStatus applies the mutate function to the latest version of the Intent status, retrying upon conflicts.
+func UpdateStatus(ctx context.Context, clt client.Client, intent *flarev1alpha1.Intent, mutate func(status *flarev1alpha1.IntentStatus)) error {
 	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
 		if err := clt.Get(ctx, client.ObjectKeyFromObject(intent), intent); err != nil {
 			return err
 		}
 
-		meta.SetStatusCondition(&intent.Status.Conditions, condition)
+		mutate(&intent.Status)
 
 		return clt.Status().Update(ctx, intent)
 	})
@@ -0,0 +1,63 @@
+// Copyright 2025 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package flavor
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	fluidosnodev1alpha1 "github.com/fluidos-project/node/apis/nodecore/v1alpha1"
+	"github.com/pkg/errors"
+)
+
+var carbonFootprintPattern = regexp.MustCompile(`(?i)^\s*(\d+(?:\.\d+)?)\s*(g|kg)\s*CO2e?\s*/\s*h\s*$`)
+
+// EmissionRate returns the estimated carbon emissions of the Flavor, expressed in grams of CO2 equivalent per hour:
+// the advertised power draw, in watts, is scaled by the data center PUE and the grid carbon intensity, in gCO2e/kWh.
+// The PUE defaults to 1 when it's not advertised.
+func EmissionRate(flavor fluidosnodev1alpha1.Flavor) (float64, bool) {
+	power, ok := GPUNumber(flavor, "power_draw_watts")
+	if !ok {
+		return 0, false
+	}
+
+	intensity, ok := GPUNumber(flavor, "carbon_intensity")
+	if !ok {
+		return 0, false
+	}
+
+	pue, ok := GPUNumber(flavor, "pue")
+	if !ok {
+		pue = 1
+	}
+
+	return power / 1000 * pue * intensity, true
+}
+
+// EnergyEfficiencyRating returns the energy efficiency rating advertised in the Flavor GPU characteristics,
+// from A (the highest) to F (the lowest).
+func EnergyEfficiencyRating(flavor fluidosnodev1alpha1.Flavor) string {
+	return strings.ToUpper(gpuString(flavor, "energy_efficiency_rating"))
+}
+
+// ParseCarbonFootprint parses a carbon footprint such as 50g CO2/h, or 1.5 kg CO2e/h,
+// returning it in grams of CO2 equivalent per hour as the Flavor emission rate.
+func ParseCarbonFootprint(value string) (float64, error) {
+	matches := carbonFootprintPattern.FindStringSubmatch(value)
+	if matches == nil {
+		return 0, errors.Errorf("invalid carbon footprint %q, expected grams or kilograms of CO2 per hour", value)
+	}
+
+	footprint, err := strconv.ParseFloat(matches[1], 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "cannot parse carbon footprint %q", value)
+	}
+
+	if strings.EqualFold(matches[2], "kg") {
+		footprint *= 1000
+	}
+
+	return footprint, nil
+}
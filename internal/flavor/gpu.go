@@ -0,0 +1,66 @@
+// Copyright 2025 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package flavor
+
+import (
+	fluidosnodev1alpha1 "github.com/fluidos-project/node/apis/nodecore/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/json"
+)
+
+// GPUCharacteristics returns the GPU characteristics advertised in the Flavor type data.
+func GPUCharacteristics(flavor fluidosnodev1alpha1.Flavor) (map[string]interface{}, bool) {
+	var obj map[string]interface{}
+	if err := json.Unmarshal(flavor.Spec.FlavorType.TypeData.Raw, &obj); err != nil {
+		return nil, false
+	}
+
+	gpu, found, err := unstructured.NestedMap(obj, "characteristics", "gpu")
+	if err != nil || !found {
+		return nil, false
+	}
+
+	return gpu, true
+}
+
+// HourlyRate returns the price per hour of the Flavor GPU characteristics, expressed in EUR.
+func HourlyRate(flavor fluidosnodev1alpha1.Flavor) (float64, bool) {
+	return GPUNumber(flavor, "hourly_rate")
+}
+
+// GPUNumber returns the numeric field of the Flavor GPU characteristics.
+func GPUNumber(flavor fluidosnodev1alpha1.Flavor, field string) (float64, bool) {
+	gpu, ok := GPUCharacteristics(flavor)
+	if !ok {
+		return 0, false
+	}
+
+	value, found, err := unstructured.NestedFloat64(gpu, field)
+	if err != nil || !found {
+		// JSON numbers with no decimals are decoded as integers.
+		intValue, intFound, intErr := unstructured.NestedInt64(gpu, field)
+		if intErr != nil || !intFound {
+			return 0, false
+		}
+
+		value = float64(intValue)
+	}
+
+	return value, true
+}
+
+// GPUBool returns the boolean field of the Flavor GPU characteristics.
+func GPUBool(flavor fluidosnodev1alpha1.Flavor, field string) (bool, bool) {
+	gpu, ok := GPUCharacteristics(flavor)
+	if !ok {
+		return false, false
+	}
+
+	value, found, err := unstructured.NestedBool(gpu, field)
+	if err != nil || !found {
+		return false, false
+	}
+
+	return value, true
+}
@@ -0,0 +1,73 @@
+// Copyright 2025 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package flavor
+
+import (
+	"strings"
+
+	fluidosnodev1alpha1 "github.com/fluidos-project/node/apis/nodecore/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// euCountries are the ISO 3166-1 alpha-2 codes of the European Union member states.
+var euCountries = sets.New[string]("AT", "BE", "BG", "HR", "CY", "CZ", "DK", "EE", "FI", "FR", "DE", "GR", "HU", "IE", "IT", "LV", "LT", "LU", "MT", "NL", "PL", "PT", "RO", "SK", "SI", "ES", "SE")
+
+// Provider returns the cloud provider advertised in the Flavor GPU characteristics.
+func Provider(flavor fluidosnodev1alpha1.Flavor) string {
+	return gpuString(flavor, "provider")
+}
+
+// Region returns the region advertised in the Flavor GPU characteristics.
+func Region(flavor fluidosnodev1alpha1.Flavor) string {
+	return gpuString(flavor, "region")
+}
+
+// AvailabilityZone returns the availability zone advertised in the Flavor GPU characteristics.
+func AvailabilityZone(flavor fluidosnodev1alpha1.Flavor) string {
+	return gpuString(flavor, "availability_zone")
+}
+
+// Certifications returns the compliance certifications advertised in the Flavor GPU characteristics.
+func Certifications(flavor fluidosnodev1alpha1.Flavor) []string {
+	gpu, ok := GPUCharacteristics(flavor)
+	if !ok {
+		return nil
+	}
+
+	certifications, _, _ := unstructured.NestedStringSlice(gpu, "certifications")
+
+	return certifications
+}
+
+// InLocation returns true when the Flavor is located in the given location, matched case-insensitively
+// against the advertised country, or region: a region matches its prefix too, e.g. EU matches eu-west-1.
+// The EU location matches the European Union member states as well.
+func InLocation(flavor fluidosnodev1alpha1.Flavor, location string) bool {
+	location = strings.ToUpper(location)
+
+	var country string
+	if flavor.Spec.Location != nil {
+		country = strings.ToUpper(flavor.Spec.Location.Country)
+	}
+
+	if country == location || (location == "EU" && euCountries.Has(country)) {
+		return true
+	}
+
+	region := strings.ToUpper(Region(flavor))
+
+	return region == location || strings.HasPrefix(region, location+"-")
+}
+
+func gpuString(flavor fluidosnodev1alpha1.Flavor, field string) string {
+	gpu, ok := GPUCharacteristics(flavor)
+	if !ok {
+		return ""
+	}
+
+	value, _, _ := unstructured.NestedString(gpu, field)
+
+	return value
+}
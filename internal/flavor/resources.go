@@ -0,0 +1,40 @@
+// Copyright 2025 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package flavor
+
+import (
+	fluidosnodev1alpha1 "github.com/fluidos-project/node/apis/nodecore/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/json"
+)
+
+// CPU returns the CPU quantity advertised in the Flavor K8Slice characteristics.
+func CPU(flavor fluidosnodev1alpha1.Flavor) (resource.Quantity, bool) {
+	return quantity(flavor, "cpu")
+}
+
+// Memory returns the memory quantity advertised in the Flavor K8Slice characteristics.
+func Memory(flavor fluidosnodev1alpha1.Flavor) (resource.Quantity, bool) {
+	return quantity(flavor, "memory")
+}
+
+func quantity(flavor fluidosnodev1alpha1.Flavor, field string) (resource.Quantity, bool) {
+	var obj map[string]interface{}
+	if err := json.Unmarshal(flavor.Spec.FlavorType.TypeData.Raw, &obj); err != nil {
+		return resource.Quantity{}, false
+	}
+
+	value, found, err := unstructured.NestedString(obj, "characteristics", field)
+	if err != nil || !found {
+		return resource.Quantity{}, false
+	}
+
+	parsed, err := resource.ParseQuantity(value)
+	if err != nil {
+		return resource.Quantity{}, false
+	}
+
+	return parsed, true
+}
@@ -7,13 +7,17 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 
 	capsulev1beta2 "github.com/projectcapsule/capsule/api/v1beta2"
 	"github.com/projectcapsule/capsule/pkg/indexer"
 	authenticationv1 "k8s.io/api/authentication/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	flarev1alpha1 "github.com/clastix/flare-internal/api/v1alpha1"
 )
 
 type Helper struct {
@@ -23,15 +27,37 @@ type Helper struct {
 
 //+kubebuilder:rbac:groups=capsule.clastix.io,resources=tenants,verbs=get;list;watch
 
+// RetrieveCapsuleTenant returns the Capsule Tenant owned by the user: ServiceAccounts are Tenant owners on their own,
+// while the users authenticated by the OIDC provider as User, or through their Groups, unless their Tenant is named by a claim.
 func (i *Helper) RetrieveCapsuleTenant(ctx context.Context, user authenticationv1.UserInfo) (*capsulev1beta2.Tenant, error) {
-	var tntList capsulev1beta2.TenantList
-	if err := i.Client.List(ctx, &tntList, client.MatchingFields{i.TenantOwnerRefIndexer.Field(): fmt.Sprintf("ServiceAccount:%s", user.Username)}); err != nil {
-		return nil, err
+	if names := user.Extra[flarev1alpha1.TenantUserExtraKey]; len(names) > 0 {
+		var tnt capsulev1beta2.Tenant
+		if err := i.Client.Get(ctx, types.NamespacedName{Name: names[0]}, &tnt); err != nil {
+			return nil, err
+		}
+
+		return &tnt, nil
+	}
+
+	owners := []string{fmt.Sprintf("ServiceAccount:%s", user.Username)}
+	if !strings.HasPrefix(user.Username, "system:serviceaccount:") {
+		owners = []string{fmt.Sprintf("User:%s", user.Username)}
+
+		for _, group := range user.Groups {
+			owners = append(owners, fmt.Sprintf("Group:%s", group))
+		}
 	}
 
-	if len(tntList.Items) == 0 {
-		return nil, &apierrors.StatusError{ErrStatus: metav1.Status{Reason: metav1.StatusReasonNotFound, Code: http.StatusNotFound}}
+	for _, owner := range owners {
+		var tntList capsulev1beta2.TenantList
+		if err := i.Client.List(ctx, &tntList, client.MatchingFields{i.TenantOwnerRefIndexer.Field(): owner}); err != nil {
+			return nil, err
+		}
+
+		if len(tntList.Items) > 0 {
+			return tntList.Items[0].DeepCopy(), nil
+		}
 	}
 
-	return tntList.Items[0].DeepCopy(), nil
+	return nil, &apierrors.StatusError{ErrStatus: metav1.Status{Reason: metav1.StatusReasonNotFound, Code: http.StatusNotFound}}
 }
@@ -4,41 +4,53 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
+	jsonpatch "github.com/evanphx/json-patch/v5"
 	fluidosv1alpha1 "github.com/fluidos-project/node/apis/advertisement/v1alpha1"
-	fluidosnodev1alpha1 "github.com/fluidos-project/node/apis/nodecore/v1alpha1"
-	"github.com/fluidos-project/node/pkg/utils/flags"
 	"github.com/labstack/echo/v4"
 	"github.com/projectcapsule/capsule/pkg/indexer"
 	authenticationv1 "k8s.io/api/authentication/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/json"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	flarev1alpha1 "github.com/clastix/flare-internal/api/v1alpha1"
 	"github.com/clastix/flare-internal/internal/api"
+	"github.com/clastix/flare-internal/internal/availability"
+	"github.com/clastix/flare-internal/internal/flavor"
 )
 
 //+kubebuilder:rbac:groups="",resources=namespaces,verbs=create;get;list;watch;delete
 //+kubebuilder:rbac:groups=advertisement.fluidos.eu,resources=peeringcandidates,verbs=get;list;watch
 
 type Intent struct {
-	Client           client.Client
-	IntentUIDIndexer indexer.CustomIndexer
-	Helper           Helper
+	Client              client.Client
+	Clientset           kubernetes.Interface
+	Informers           cache.Informers
+	IntentUIDIndexer    indexer.CustomIndexer
+	IntentDomainIndexer indexer.CustomIndexer
+	Helper              Helper
+	RESTConfig          *rest.Config
+	IngressDomain       string
 }
 
 func (i *Intent) ListIntents(ctx echo.Context) error {
@@ -83,37 +95,111 @@ func (i *Intent) ListIntents(ctx echo.Context) error {
 
 func (i *Intent) formatIntentToAPI(intent flarev1alpha1.Intent) api.IntentStatus {
 	return api.IntentStatus{
+		CarbonFootprint: func() *string {
+			emissions := intent.Status.Emissions
+			if intent.Status.Cost.LastMeteringTime == nil || (emissions.Rate == 0 && emissions.Accrued == 0) {
+				return nil
+			}
+			// Accruing the emissions since the last metering performed by the operator, as for the cost.
+			current := emissions.Accrued
+			if !budgetExceeded(intent) && !outsideAvailability(intent) {
+				current += emissions.Rate * time.Since(intent.Status.Cost.LastMeteringTime.Time).Hours() / 1000
+			}
+
+			return ptr.To(strconv.FormatFloat(current, 'f', 2, 64) + " kgCO2e")
+		}(),
 		CurrentCost: func() *string {
-			if intent.Spec.Constraints.MaxHourlyCost == float64(0) {
+			cost := intent.Status.Cost
+			if cost.LastMeteringTime == nil {
 				return nil
 			}
+			// Accruing the cost since the last metering performed by the operator:
+			// the suspended workload still holds its allocation, which is paid for, unless the budget is exceeded.
+			current := cost.Accrued
+			if !budgetExceeded(intent) {
+				current += cost.HourlyRate * time.Since(cost.LastMeteringTime.Time).Hours()
+			}
 
-			runHours := intent.Spec.Constraints.MaxHourlyCost * time.Now().Sub(intent.CreationTimestamp.Time).Truncate(time.Hour).Hours()
-			if runHours == 0 {
-				runHours++
+			return ptr.To(strconv.FormatFloat(current, 'f', 2, 64) + " EUR")
+		}(),
+		Endpoints: func() *[]api.Endpoint {
+			if len(intent.Status.Endpoints) == 0 {
+				return nil
 			}
 
-			parsedFloat := strconv.FormatFloat(runHours, 'g', -1, 64)
+			endpoints := make([]api.Endpoint, 0, len(intent.Status.Endpoints))
+			for _, endpoint := range intent.Status.Endpoints {
+				endpoints = append(endpoints, api.Endpoint{
+					Port: ptr.To(int(endpoint.Port)),
+					Url:  ptr.To(endpoint.URL),
+				})
+			}
 
-			return ptr.To(parsedFloat + " EUR")
+			return &endpoints
 		}(),
 		GpuUtilization: nil,
 		IntentId:       ptr.To(string(intent.UID)),
 		Message: func() *string {
+			if batchWorkloadCompleted(intent) {
+				workload := meta.FindStatusCondition(intent.Status.Conditions, flarev1alpha1.IntentStatusTypeWorkload)
+
+				return ptr.To("Workload completed successfully (" + workload.Message + ")")
+			}
+
 			for _, condition := range intent.Status.Conditions {
 				if condition.Status == metav1.ConditionFalse {
 					return ptr.To(condition.Message + "(" + condition.Reason + ")")
 				}
 			}
 
+			if budget := meta.FindStatusCondition(intent.Status.Conditions, flarev1alpha1.IntentStatusTypeBudget); budget != nil && budget.Reason == flarev1alpha1.IntentBudgetReasonWarning {
+				return ptr.To("Intent running successfully, " + budget.Message)
+			}
+
 			return ptr.To("Intent running successfully")
 		}(),
+		QueuePosition: func() *int {
+			if !intentQueued(intent) || intent.Status.Queue.Position == 0 {
+				return nil
+			}
+
+			return ptr.To(intent.Status.Queue.Position)
+		}(),
 		Runtime: ptr.To(time.Now().Sub(intent.CreationTimestamp.Time).Truncate(time.Second).String()),
 		Status: func() *string {
+			if intent.DeletionTimestamp != nil {
+				return ptr.To("Terminating")
+			}
+
 			if len(intent.Status.Conditions) == 0 {
 				return ptr.To("Pending")
 			}
 
+			if intent.Spec.Workload.Type == flarev1alpha1.IntentWorkloadTypeBatch {
+				workload := meta.FindStatusCondition(intent.Status.Conditions, flarev1alpha1.IntentStatusTypeWorkload)
+
+				switch {
+				case workload == nil:
+					break
+				case workload.Reason == flarev1alpha1.IntentWorkloadReasonSucceeded:
+					return ptr.To("Completed")
+				case workload.Reason == flarev1alpha1.IntentWorkloadReasonFailed:
+					return ptr.To("Failed")
+				}
+			}
+
+			if intentQueued(intent) {
+				return ptr.To("Queued")
+			}
+
+			if budgetExceeded(intent) {
+				return ptr.To("Suspended")
+			}
+
+			if outsideAvailability(intent) {
+				return ptr.To("Scheduled")
+			}
+
 			for _, condition := range intent.Status.Conditions {
 				if condition.Status == metav1.ConditionFalse {
 					return ptr.To(condition.Type + "NotReady")
@@ -123,12 +209,16 @@ func (i *Intent) formatIntentToAPI(intent flarev1alpha1.Intent) api.IntentStatus
 			return ptr.To("Ready")
 		}(),
 		WorkloadUrl: func() *string {
-			for _, port := range intent.Spec.Workload.Ports {
-				if !port.Expose || port.Domain == "" {
-					continue
+			// The operator tracks the URLs along with the assigned domains, and the TLS availability:
+			// the HTTP endpoints are preferred to the ones of the raw TCP and UDP ports.
+			for _, endpoint := range intent.Status.Endpoints {
+				if strings.HasPrefix(endpoint.URL, "http") {
+					return ptr.To(endpoint.URL)
 				}
+			}
 
-				return ptr.To("https://" + port.Domain)
+			if len(intent.Status.Endpoints) > 0 {
+				return ptr.To(intent.Status.Endpoints[0].URL)
 			}
 
 			return nil
@@ -136,6 +226,82 @@ func (i *Intent) formatIntentToAPI(intent flarev1alpha1.Intent) api.IntentStatus
 	}
 }
 
+// validateDomains ensures the custom domains of the exposed ports are not already claimed by any other Intent,
+// regardless of its Tenant: the given UID is the one of the Intent being updated.
+// The wildcard domain is reserved to the ports with no custom domain, thus they can't clash with the custom ones.
+func (i *Intent) validateDomains(ctx context.Context, spec flarev1alpha1.IntentSpec, uid types.UID) *intentSpecError {
+	domains := sets.New[string]()
+
+	for _, port := range spec.Workload.Ports {
+		if !port.Expose || port.Domain == "" {
+			continue
+		}
+
+		if i.IngressDomain != "" && (port.Domain == i.IngressDomain || strings.HasSuffix(port.Domain, "."+i.IngressDomain)) {
+			return &intentSpecError{
+				code:    http.StatusBadRequest,
+				err:     "custom domain cannot be under the wildcard domain " + i.IngressDomain,
+				context: port.Domain,
+			}
+		}
+
+		domains.Insert(port.Domain)
+	}
+
+	// The operator exposes a domain for the Intent claiming it first, the concurrent submissions notwithstanding.
+	for _, domain := range sets.List(domains) {
+		var intentList flarev1alpha1.IntentList
+		if err := i.Client.List(ctx, &intentList, client.MatchingFields(fields.Set{i.IntentDomainIndexer.Field(): domain})); err != nil {
+			return &intentSpecError{
+				code:    http.StatusInternalServerError,
+				err:     err.Error(),
+				context: "cannot retrieve list of Intents",
+			}
+		}
+
+		for _, intent := range intentList.Items {
+			if intent.UID != uid {
+				return &intentSpecError{
+					code:    http.StatusConflict,
+					err:     "domain is already claimed by another intent",
+					context: domain,
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// intentQueued returns true when the Intent is waiting in the queue for a new discovery, upon the lack of capacity.
+func intentQueued(intent flarev1alpha1.Intent) bool {
+	// The workload keeps running on its allocation while the discovery of a migration is queued.
+	return intent.Status.Queue != nil && intent.Status.Queue.NextAttemptTime != nil && intent.Status.Migration == nil
+}
+
+// budgetExceeded returns true when the workload has been suspended upon the budget exhaustion.
+func budgetExceeded(intent flarev1alpha1.Intent) bool {
+	budget := meta.FindStatusCondition(intent.Status.Conditions, flarev1alpha1.IntentStatusTypeBudget)
+
+	return budget != nil && budget.Reason == flarev1alpha1.IntentBudgetReasonExceeded
+}
+
+// outsideAvailability returns true when the workload is held, or suspended, until its next availability window.
+func outsideAvailability(intent flarev1alpha1.Intent) bool {
+	return availability.Outside(intent.Status.Conditions)
+}
+
+// batchWorkloadCompleted returns true when the Job of a batch Intent has been completed successfully.
+func batchWorkloadCompleted(intent flarev1alpha1.Intent) bool {
+	if intent.Spec.Workload.Type != flarev1alpha1.IntentWorkloadTypeBatch {
+		return false
+	}
+
+	workload := meta.FindStatusCondition(intent.Status.Conditions, flarev1alpha1.IntentStatusTypeWorkload)
+
+	return workload != nil && workload.Reason == flarev1alpha1.IntentWorkloadReasonSucceeded
+}
+
 func (i *Intent) SubmitIntent(ctx echo.Context) error {
 	user := ctx.Get("user").(authenticationv1.UserInfo)
 
@@ -152,6 +318,25 @@ func (i *Intent) SubmitIntent(ctx echo.Context) error {
 		})
 	}
 
+	spec, specErr := intentSpecFromAPI(body.Intent)
+	if specErr != nil {
+		return specErr.reply(ctx)
+	}
+
+	inline := inlineSecrets(body.Intent)
+
+	if specErr = validateSecretValues(ctx.Request().Context(), i.Client, nil, spec, inline); specErr != nil {
+		return specErr.reply(ctx)
+	}
+
+	submission, err := json.Marshal(body.Intent)
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{
+			"error":   err.Error(),
+			"context": "cannot encode Intent submission",
+		})
+	}
+
 	tnt, notFoundErr := i.Helper.RetrieveCapsuleTenant(ctx.Request().Context(), user)
 	if notFoundErr != nil {
 		if apierrors.IsNotFound(notFoundErr) {
@@ -166,6 +351,14 @@ func (i *Intent) SubmitIntent(ctx echo.Context) error {
 		})
 	}
 
+	if specErr = validateSecretReferences(ctx.Request().Context(), i.Client, tnt.Name, spec); specErr != nil {
+		return specErr.reply(ctx)
+	}
+
+	if specErr = i.validateDomains(ctx.Request().Context(), spec, ""); specErr != nil {
+		return specErr.reply(ctx)
+	}
+
 	var ns corev1.Namespace
 	ns.GenerateName = fmt.Sprintf("%s-", tnt.Name)
 
@@ -186,542 +379,167 @@ func (i *Intent) SubmitIntent(ctx echo.Context) error {
 	var intent flarev1alpha1.Intent
 	intent.Namespace = ns.Name
 	intent.Name = strings.ReplaceAll(ns.Name, ns.GenerateName, "")
+	// The submission is the base document for the partial updates of the Intent.
+	intent.Annotations = map[string]string{
+		flarev1alpha1.IntentSubmissionAnnotation: string(submission),
+	}
 
-	if body.Intent.Constraints != nil {
-		if body.Intent.Constraints.Availability != nil {
-			if body.Intent.Constraints.Availability.BlackoutDates != nil {
-				intent.Spec.Constraints.Availability.BlackoutDates = make([]string, 0, len(*body.Intent.Constraints.Availability.BlackoutDates))
-				for _, date := range *body.Intent.Constraints.Availability.BlackoutDates {
-					intent.Spec.Constraints.Availability.BlackoutDates = append(intent.Spec.Constraints.Availability.BlackoutDates, date.String())
-				}
-			}
-
-			intent.Spec.Constraints.Availability.DaysOfWeek = make([]flarev1alpha1.DayOfWeek, 0, len(body.Intent.Constraints.Availability.DaysOfWeek))
-			for _, day := range body.Intent.Constraints.Availability.DaysOfWeek {
-				intent.Spec.Constraints.Availability.DaysOfWeek = append(intent.Spec.Constraints.Availability.DaysOfWeek, flarev1alpha1.DayOfWeek(day))
-			}
-
-			intent.Spec.Constraints.Availability.MaintenanceWindows = make([]flarev1alpha1.IntentConstraintAvailabilityMaintenanceWindow, 0, len(body.Intent.Constraints.Availability.MaintenanceWindows))
-			for _, window := range body.Intent.Constraints.Availability.MaintenanceWindows {
-				var maintenanceWindow flarev1alpha1.IntentConstraintAvailabilityMaintenanceWindow
-
-				switch {
-				case window.Frequency == nil:
-					break
-				case *window.Frequency == api.Monthly:
-					maintenanceWindow.Frequency = "Monthly"
-				case *window.Frequency == api.Weekly:
-					maintenanceWindow.Frequency = "Weekly"
-				default:
-					return ctx.JSON(http.StatusBadRequest, map[string]string{
-						"error":   "unhandled frequency enum",
-						"context": string(*window.Frequency),
-					})
-				}
-
-				maintenanceWindow.Start = window.Start.String()
-				maintenanceWindow.End = window.End.String()
-
-				intent.Spec.Constraints.Availability.MaintenanceWindows = append(intent.Spec.Constraints.Availability.MaintenanceWindows, maintenanceWindow)
-			}
-
-			intent.Spec.Constraints.Availability.Timezone = body.Intent.Constraints.Availability.Timezone
-			intent.Spec.Constraints.Availability.WindowEnd = body.Intent.Constraints.Availability.WindowEnd
-			intent.Spec.Constraints.Availability.WindowStart = body.Intent.Constraints.Availability.WindowStart
-		}
-
-		if body.Intent.Constraints.AvailabilityZone != nil {
-			intent.Spec.Constraints.AvailabilityZone = *body.Intent.Constraints.AvailabilityZone
-		}
-
-		if body.Intent.Constraints.Compliance != nil {
-			intent.Spec.Constraints.Compliance.AuditLogging = ptr.Deref(body.Intent.Constraints.Compliance.AuditLogging, false)
-
-			if body.Intent.Constraints.Compliance.Certifications != nil {
-				intent.Spec.Constraints.Compliance.Certifications = make([]flarev1alpha1.Certification, 0, len(*body.Intent.Constraints.Compliance.Certifications))
-				for _, cert := range *body.Intent.Constraints.Compliance.Certifications {
-					switch cert {
-					case api.ISO27001, api.SOC2:
-						intent.Spec.Constraints.Compliance.Certifications = append(intent.Spec.Constraints.Compliance.Certifications, flarev1alpha1.Certification(cert))
-					default:
-						return ctx.JSON(http.StatusBadRequest, map[string]string{
-							"error":   "unhandled certification enum",
-							"context": string(cert),
-						})
-					}
-				}
-			}
-
-			if body.Intent.Constraints.Compliance.DataResidency != nil {
-				intent.Spec.Constraints.Compliance.DataResidency = make([]string, 0, len(*body.Intent.Constraints.Compliance.DataResidency))
-				for _, dataResidency := range *body.Intent.Constraints.Compliance.DataResidency {
-					intent.Spec.Constraints.Compliance.DataResidency = append(intent.Spec.Constraints.Compliance.DataResidency, string(dataResidency))
-				}
-			}
-
-			intent.Spec.Constraints.Compliance.EncryptionAtRest = ptr.Deref(body.Intent.Constraints.Compliance.EncryptionAtRest, false)
-			intent.Spec.Constraints.Compliance.EncryptionInTransit = ptr.Deref(body.Intent.Constraints.Compliance.EncryptionInTransit, false)
-			intent.Spec.Constraints.Compliance.GDPRCompliant = ptr.Deref(body.Intent.Constraints.Compliance.GdprCompliant, false)
-			intent.Spec.Constraints.Compliance.HIPPACompliant = ptr.Deref(body.Intent.Constraints.Compliance.HipaaCompliant, false)
-		}
-
-		if body.Intent.Constraints.Deadline != nil {
-			intent.Spec.Constraints.Deadline = metav1.Time{Time: *body.Intent.Constraints.Deadline}
-		}
-
-		if body.Intent.Constraints.Energy != nil {
-			intent.Spec.Constraints.Energy.EnergyEfficiencyRating = ptr.Deref(body.Intent.Constraints.Energy.EnergyEfficiencyRating, "")
-			intent.Spec.Constraints.Energy.GreenCertifiedOnly = ptr.Deref(body.Intent.Constraints.Energy.GreenCertifiedOnly, false)
-			intent.Spec.Constraints.Energy.MaxCarbonFootprint = ptr.Deref(body.Intent.Constraints.Energy.MaxCarbonFootprint, "")
-			intent.Spec.Constraints.Energy.PowerUsageEffectiveness = ptr.Deref(body.Intent.Constraints.Energy.PowerUsageEffectiveness, float32(0))
-			intent.Spec.Constraints.Energy.RenewableEnergyOnly = ptr.Deref(body.Intent.Constraints.Energy.RenewableEnergyOnly, false)
-		}
-
-		intent.Spec.Constraints.Location = ptr.Deref(body.Intent.Constraints.Location, "")
-		if body.Intent.Constraints.MaxHourlyCost != nil {
-			maxHourlyCost := strings.ReplaceAll(*body.Intent.Constraints.MaxHourlyCost, "EUR", "")
-			maxHourlyCost = strings.TrimSpace(maxHourlyCost)
-			intent.Spec.Constraints.MaxHourlyCost, _ = strconv.ParseFloat(maxHourlyCost, 32)
-		}
-
-		intent.Spec.Constraints.MaxLatencyMs = int64(ptr.Deref(body.Intent.Constraints.MaxLatencyMs, 0))
-
-		if body.Intent.Constraints.MaxTotalCost != nil {
-			maxTotalCost := strings.ReplaceAll(*body.Intent.Constraints.MaxTotalCost, "EUR", "")
-			maxTotalCost = strings.TrimSpace(maxTotalCost)
-			intent.Spec.Constraints.MaxTotalCost, _ = strconv.ParseFloat(maxTotalCost, 32)
-		}
-
-		if body.Intent.Constraints.Negotiation != nil {
-			intent.Spec.Constraints.Negotiation.AutoAcceptThreshold = float64(ptr.Deref(body.Intent.Constraints.Negotiation.AutoAcceptThreshold, float32(0)))
-			intent.Spec.Constraints.Negotiation.FallbackStrategy = ptr.Deref(body.Intent.Constraints.Negotiation.FallbackStrategy, "")
-			intent.Spec.Constraints.Negotiation.MaxNegotiationRounds = ptr.Deref(body.Intent.Constraints.Negotiation.MaxNegotiationRounds, 0)
-			intent.Spec.Constraints.Negotiation.PriceFlexibility = float64(ptr.Deref(body.Intent.Constraints.Negotiation.PriceFlexibility, float32(0)))
-			intent.Spec.Constraints.Negotiation.ResourceFlexibility = float64(ptr.Deref(body.Intent.Constraints.Negotiation.ResourceFlexibility, float32(0)))
-			intent.Spec.Constraints.Negotiation.TimeoutSeconds = int64(ptr.Deref(body.Intent.Constraints.Negotiation.TimeoutSeconds, 0))
-		}
-
-		if body.Intent.Constraints.Performance != nil {
-			intent.Spec.Constraints.Performance.GpuUtilizationTarget = float64(ptr.Deref(body.Intent.Constraints.Performance.GpuUtilizationTarget, float32(0)))
-
-			if body.Intent.Constraints.Performance.MaxColdStartTime != nil {
-				if d, err := time.ParseDuration(*body.Intent.Constraints.Performance.MaxColdStartTime); err != nil {
-					return ctx.JSON(http.StatusBadRequest, map[string]string{
-						"error":   "cannot parse max cold start time value",
-						"context": *body.Intent.Constraints.Performance.MaxColdStartTime,
-					})
-				} else {
-					intent.Spec.Constraints.Performance.MaxColdStartTime = metav1.Duration{Duration: d}
-				}
-			}
-
-			intent.Spec.Constraints.Performance.MaxJitterMs = int64(ptr.Deref(body.Intent.Constraints.Performance.MaxJitterMs, 0))
-			intent.Spec.Constraints.Performance.MemoryUtilizationTarget = float64(ptr.Deref(body.Intent.Constraints.Performance.MemoryUtilizationTarget, float32(0)))
-
-			if body.Intent.Constraints.Performance.MinNetworkBandwidth != nil {
-				qty, qErr := resource.ParseQuantity(*body.Intent.Constraints.Performance.MinNetworkBandwidth)
-				if qErr != nil {
-					return ctx.JSON(http.StatusBadRequest, map[string]string{
-						"error":   "cannot parse quantity for minimum network bandwidth",
-						"context": *body.Intent.Constraints.Performance.MaxColdStartTime,
-					})
-				}
-
-				intent.Spec.Constraints.Performance.MinNetworkBandwidth = qty
-			}
-
-			intent.Spec.Constraints.Performance.MinUptimePercent = float64(ptr.Deref(body.Intent.Constraints.Performance.MinUptimePercent, float32(0)))
-		}
-
-		intent.Spec.Constraints.PreEmptible = ptr.Deref(body.Intent.Constraints.Preemptible, false)
-
-		if body.Intent.Constraints.Providers != nil {
-			intent.Spec.Constraints.Providers = make([]string, 0, len(*body.Intent.Constraints.Providers))
-			for _, provider := range *body.Intent.Constraints.Providers {
-				intent.Spec.Constraints.Providers = append(intent.Spec.Constraints.Providers, string(provider))
-			}
-		}
-
-		if body.Intent.Constraints.Security != nil {
-			intent.Spec.Constraints.Security.BastionHost = ptr.Deref(body.Intent.Constraints.Security.BastionHost, false)
-
-			if body.Intent.Constraints.Security.FirewallRules != nil {
-				intent.Spec.Constraints.Security.FirewallRules = make([]flarev1alpha1.IntentWorkloadConstraintSecurityFirewallRule, 0, len(*body.Intent.Constraints.Security.FirewallRules))
-
-				for _, rule := range *body.Intent.Constraints.Security.FirewallRules {
-					var r flarev1alpha1.IntentWorkloadConstraintSecurityFirewallRule
-
-					r.Port = int32(rule.Port)
-					r.Source = rule.Source
-					r.Protocol = rule.Protocol
-
-					switch rule.Action {
-					case api.Allow, api.Deny:
-						r.Action = string(rule.Action)
-					default:
-						return ctx.JSON(http.StatusBadRequest, map[string]string{
-							"error":   "unhandled action enum",
-							"context": string(rule.Action),
-						})
-					}
-					intent.Spec.Constraints.Security.FirewallRules = append(intent.Spec.Constraints.Security.FirewallRules, r)
-				}
-			}
-
-			intent.Spec.Constraints.Security.IntrusionDetection = ptr.Deref(body.Intent.Constraints.Security.IntrusionDetection, false)
-
-			if body.Intent.Constraints.Security.NetworkIsolation != nil {
-				switch *body.Intent.Constraints.Security.NetworkIsolation {
-				case api.Private, api.Public:
-					intent.Spec.Constraints.Security.NetworkIsolation = string(*body.Intent.Constraints.Security.NetworkIsolation)
-				default:
-					return ctx.JSON(http.StatusBadRequest, map[string]string{
-						"error":   "unhandled network isolation enum",
-						"context": string(*body.Intent.Constraints.Security.NetworkIsolation),
-					})
-				}
-
-			}
+	intent.Spec = spec
 
-			intent.Spec.Constraints.Security.VpnAccess = ptr.Deref(body.Intent.Constraints.Security.VpnAccess, false)
-			intent.Spec.Constraints.Security.VulnerabilityScanning = ptr.Deref(body.Intent.Constraints.Security.VulnerabilityScanning, false)
-		}
+	if err := storeInlineSecrets(ctx.Request().Context(), i.Client, &intent, inline); err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{
+			"error":   err.Error(),
+			"context": "cannot store inline secrets",
+		})
 	}
 
-	switch body.Intent.Objective {
-	case api.BalancedOptimization:
-		intent.Spec.Objective = flarev1alpha1.IntentObjectBalancedOptimization
-	case api.CostMinimization:
-		intent.Spec.Objective = flarev1alpha1.IntentObjectCostMinimization
-	case api.EnergyEfficiency:
-		intent.Spec.Objective = flarev1alpha1.IntentObjectEnergyEfficiency
-	case api.LatencyMinimization:
-		intent.Spec.Objective = flarev1alpha1.IntentObjectLatencyMinimization
-	case api.PerformanceMaximization:
-		intent.Spec.Objective = flarev1alpha1.IntentObjectPerformanceMaximization
-	default:
+	if err := i.Client.Create(ctx.Request().Context(), &intent); err != nil {
 		return ctx.JSON(http.StatusInternalServerError, map[string]string{
-			"error":   "unhandled objective type",
-			"context": string(body.Intent.Objective),
+			"error":   err.Error(),
+			"context": "cannot create Intent",
 		})
 	}
 
-	if body.Intent.Sla != nil {
-		intent.Spec.SLA.Availability = ptr.Deref(body.Intent.Sla.Availability, "")
-		intent.Spec.SLA.BackupStrategy = ptr.Deref(body.Intent.Sla.BackupStrategy, "")
-
-		if body.Intent.Sla.MaxInterruptionTime != nil {
-			if d, err := time.ParseDuration(*body.Intent.Sla.MaxInterruptionTime); err != nil {
-				return ctx.JSON(http.StatusBadRequest, map[string]string{
-					"error":   "cannot parse max interruption time value",
-					"context": *body.Intent.Sla.MaxInterruptionTime,
-				})
-			} else {
-				intent.Spec.SLA.MaxInterruptionTime = &metav1.Duration{Duration: d}
-			}
-		}
-	}
+	return ctx.JSON(200, api.SubmitIntentResponse{
+		EstimatedCost:      i.estimateCost(ctx.Request().Context(), intent.Spec),
+		EstimatedStartTime: nil,
+		IntentId:           ptr.To(string(intent.UID)),
+		Message:            ptr.To("Intent received and processing"),
+		Status:             ptr.To("Pending"),
+	})
+}
 
-	if body.Intent.Workload.Commands != nil {
-		intent.Spec.Workload.Commands = *body.Intent.Workload.Commands
-	}
+// CancelIntent deletes the Intent, the teardown of the allocated resources
+// is performed by the operator through the cleanup finalizer.
+func (i *Intent) CancelIntent(ctx echo.Context, intentId string) error {
+	user := ctx.Get("user").(authenticationv1.UserInfo)
 
-	if body.Intent.Workload.CommunicationPattern != nil {
-		switch *body.Intent.Workload.CommunicationPattern {
-		case api.AllReduce:
-			intent.Spec.Workload.CommunicationPattern = "AllReduce"
-		case api.Independent:
-			intent.Spec.Workload.CommunicationPattern = "Independent"
-		case api.Pipeline:
-			intent.Spec.Workload.CommunicationPattern = "Pipeline"
-		default:
+	tnt, notFoundErr := i.Helper.RetrieveCapsuleTenant(ctx.Request().Context(), user)
+	if notFoundErr != nil {
+		if apierrors.IsNotFound(notFoundErr) {
 			return ctx.JSON(http.StatusBadRequest, map[string]string{
-				"error":   "unhandled communication pattern enum",
-				"context": string(*body.Intent.Workload.CommunicationPattern),
+				"error": "user is not assigned to any Tenant",
 			})
 		}
-	}
 
-	if body.Intent.Workload.DeploymentStrategy != nil {
-		switch *body.Intent.Workload.DeploymentStrategy {
-		case api.Colocated:
-			intent.Spec.Workload.DeploymentStrategy = "Colocated"
-		case api.Distributed:
-			intent.Spec.Workload.DeploymentStrategy = "Distributed"
-		case api.Flexibile:
-			intent.Spec.Workload.DeploymentStrategy = "Flexible"
-		default:
-			return ctx.JSON(http.StatusBadRequest, map[string]string{
-				"error":   "unhandled deployment strategy enum",
-				"context": string(*body.Intent.Workload.DeploymentStrategy),
-			})
-		}
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{
+			"error":   notFoundErr.Error(),
+			"context": "cannot retrieve list of Tenants",
+		})
 	}
 
-	if body.Intent.Workload.Env != nil {
-		intent.Spec.Workload.Env = append(intent.Spec.Workload.Env, *body.Intent.Workload.Env...)
+	var intentList flarev1alpha1.IntentList
+	if err := i.Client.List(ctx.Request().Context(), &intentList, client.MatchingFields(fields.Set{i.IntentUIDIndexer.Field(): intentId})); err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{
+			"error":   err.Error(),
+			"context": "cannot retrieve list of Intents by UID",
+		})
 	}
 
-	intent.Spec.Workload.Image = body.Intent.Workload.Image
-	intent.Spec.Workload.Name = body.Intent.Workload.Name
-
-	switch body.Intent.Workload.Type {
-	case api.WorkloadTypeBatch:
-		intent.Spec.Workload.Type = flarev1alpha1.IntentWorkloadTypeBatch
-
-		if body.Intent.Workload.Batch != nil {
-			switch *body.Intent.Workload.Batch.CompletionPolicy {
-			case api.BatchCompletionPolicyAll, api.BatchCompletionPolicyAny:
-				intent.Spec.Workload.Batch.CompletionPolicy = string(*body.Intent.Workload.Batch.CompletionPolicy)
-			default:
-				return ctx.JSON(http.StatusBadRequest, map[string]string{
-					"error":   "unhandled completion policy enum",
-					"context": string(*body.Intent.Workload.Batch.CompletionPolicy),
-				})
-			}
-
-			intent.Spec.Workload.Batch.MaxRetries = ptr.Deref(body.Intent.Workload.Batch.ParallelTasks, 3)
-			intent.Spec.Workload.Batch.ParallelTasks = ptr.Deref(body.Intent.Workload.Batch.ParallelTasks, 1)
-
-			if body.Intent.Workload.Batch.Timeout != nil {
-				if d, dErr := time.ParseDuration(*body.Intent.Workload.Batch.Timeout); dErr != nil {
-					return ctx.JSON(http.StatusBadRequest, map[string]string{
-						"error":   "cannot parse job timeout value",
-						"context": *body.Intent.Workload.Batch.Timeout,
-					})
-				} else {
-					intent.Spec.Workload.Batch.Timeout = metav1.Duration{Duration: d}
-				}
-			}
-		}
-	case api.WorkloadTypeService:
-		intent.Spec.Workload.Type = flarev1alpha1.IntentWorkloadTypeService
-
-		if body.Intent.Workload.Scaling != nil {
-			intent.Spec.Workload.Scaling.AutoScale = ptr.Deref(body.Intent.Workload.Scaling.AutoScale, false)
-			intent.Spec.Workload.Scaling.MaxReplicas = ptr.Deref(body.Intent.Workload.Scaling.MaxReplicas, 10)
-			intent.Spec.Workload.Scaling.MinReplicas = ptr.Deref(body.Intent.Workload.Scaling.MinReplicas, 1)
-			intent.Spec.Workload.Scaling.TargetCpuPercent = ptr.Deref(body.Intent.Workload.Scaling.TargetCpuPercent, 70)
-			intent.Spec.Workload.Scaling.TargetGpuPercent = ptr.Deref(body.Intent.Workload.Scaling.TargetGpuPercent, 80)
-
-		}
-	default:
-		return ctx.JSON(http.StatusBadRequest, map[string]string{
-			"error":   "unhandled workload type",
-			"context": string(body.Intent.Workload.Type),
+	if len(intentList.Items) == 0 {
+		return ctx.JSON(http.StatusNotFound, map[string]string{
+			"err": "intent not found",
 		})
 	}
 
-	if body.Intent.Workload.Ports != nil {
-		for _, port := range *body.Intent.Workload.Ports {
-			intent.Spec.Workload.Ports = append(intent.Spec.Workload.Ports, flarev1alpha1.IntentWorkloadPort{
-				Port: int32(port.Port),
-				Protocol: func() string {
-					if port.Protocol != nil {
-						return string(*port.Protocol)
-					}
-
-					return ""
-				}(),
-				Expose: ptr.Deref(port.Expose, false),
-				Domain: ptr.Deref(port.Domain, ""),
+	namespaceList := sets.New[string](tnt.Status.Namespaces...)
+	for _, intent := range intentList.Items {
+		if !namespaceList.Has(intent.Namespace) {
+			return ctx.JSON(http.StatusNotFound, map[string]string{
+				"err": "intent not found",
 			})
 		}
-	}
 
-	if body.Intent.Workload.Resources.Cpu != nil {
-		qty, qErr := resource.ParseQuantity(*body.Intent.Workload.Resources.Cpu)
-		if qErr != nil {
-			return ctx.JSON(http.StatusBadRequest, map[string]string{
-				"error":   "cannot parse quantity for CPU",
-				"context": *body.Intent.Workload.Resources.Cpu,
+		if err := i.Client.Delete(ctx.Request().Context(), &intent); err != nil && !apierrors.IsNotFound(err) {
+			return ctx.JSON(http.StatusInternalServerError, map[string]string{
+				"error":   err.Error(),
+				"context": "cannot delete Intent",
 			})
 		}
-
-		intent.Spec.Workload.Resources.CPU = qty
 	}
 
-	if body.Intent.Workload.Resources.Gpu != nil {
-		switch {
-		case body.Intent.Workload.Resources.Gpu.Architecture == nil, *body.Intent.Workload.Resources.Gpu.Architecture == "any":
-			intent.Spec.Workload.Resources.GPU.Architecture = "Any"
-		default:
-			intent.Spec.Workload.Resources.GPU.Architecture = string(*body.Intent.Workload.Resources.Gpu.Architecture)
-		}
-
-		if body.Intent.Workload.Resources.Gpu.ClockSpeedMin != nil {
-			qty, qErr := resource.ParseQuantity(*body.Intent.Workload.Resources.Gpu.ClockSpeedMin)
-			if qErr != nil {
-				return ctx.JSON(http.StatusBadRequest, map[string]string{
-					"error":   "cannot parse quantity for clock speed",
-					"context": *body.Intent.Workload.Resources.Gpu.ClockSpeedMin,
-				})
-			}
-
-			intent.Spec.Workload.Resources.GPU.ClockSpeedMin = qty
-		}
-
-		switch {
-		case body.Intent.Workload.Resources.Gpu.ComputeCapability == nil, *body.Intent.Workload.Resources.Gpu.ComputeCapability == "any":
-			intent.Spec.Workload.Resources.GPU.ComputeCapability = "Any"
-		default:
-			intent.Spec.Workload.Resources.GPU.ComputeCapability = string(*body.Intent.Workload.Resources.Gpu.ComputeCapability)
-		}
-
-		intent.Spec.Workload.Resources.GPU.CoresMax = int64(ptr.Deref(body.Intent.Workload.Resources.Gpu.CoresMax, 0))
-		intent.Spec.Workload.Resources.GPU.CoresMin = int64(ptr.Deref(body.Intent.Workload.Resources.Gpu.CoresMin, 0))
-		intent.Spec.Workload.Resources.GPU.Count = int64(ptr.Deref(body.Intent.Workload.Resources.Gpu.Count, 0))
-		intent.Spec.Workload.Resources.GPU.Dedicated = body.Intent.Workload.Resources.Gpu.Dedicated
-		intent.Spec.Workload.Resources.GPU.FP32TFlops = float64(ptr.Deref(body.Intent.Workload.Resources.Gpu.Fp32Tflops, float32(0)))
-		intent.Spec.Workload.Resources.GPU.Interconnect = ptr.Deref(body.Intent.Workload.Resources.Gpu.Interconnect, "")
-		intent.Spec.Workload.Resources.GPU.Interruptible = body.Intent.Workload.Resources.Gpu.Interruptible
-		if body.Intent.Workload.Resources.Gpu.Interruptible != nil {
-		}
-
-		if body.Intent.Workload.Resources.Gpu.MemoryMax != nil {
-			qty, qErr := resource.ParseQuantity(*body.Intent.Workload.Resources.Gpu.MemoryMax)
-			if qErr != nil {
-				return ctx.JSON(http.StatusBadRequest, map[string]string{
-					"error":   "cannot parse quantity for max memory",
-					"context": *body.Intent.Workload.Resources.Gpu.MemoryMax,
-				})
-			}
-
-			intent.Spec.Workload.Resources.GPU.MemoryMax = qty
-		}
-
-		if body.Intent.Workload.Resources.Gpu.MemoryMin != nil {
-			qty, qErr := resource.ParseQuantity(*body.Intent.Workload.Resources.Gpu.MemoryMin)
-			if qErr != nil {
-				return ctx.JSON(http.StatusBadRequest, map[string]string{
-					"error":   "cannot parse quantity for min memory",
-					"context": *body.Intent.Workload.Resources.Gpu.MemoryMin,
-				})
-			}
-
-			intent.Spec.Workload.Resources.GPU.MemoryMin = qty
-		}
-
-		switch {
-		case body.Intent.Workload.Resources.Gpu.Model == nil, *body.Intent.Workload.Resources.Gpu.Model == "any":
-			intent.Spec.Workload.Resources.GPU.Model = "Any"
-		default:
-			intent.Spec.Workload.Resources.GPU.Model = string(*body.Intent.Workload.Resources.Gpu.Model)
-		}
+	return ctx.JSON(http.StatusAccepted, nil)
+}
 
-		intent.Spec.Workload.Resources.GPU.MultiGPUEfficiency = float64(ptr.Deref(body.Intent.Workload.Resources.Gpu.MultiGpuEfficiency, float32(0)))
-		intent.Spec.Workload.Resources.GPU.MultiInstance = body.Intent.Workload.Resources.Gpu.MultiInstance
-		intent.Spec.Workload.Resources.GPU.Shared = body.Intent.Workload.Resources.Gpu.Shared
+func (i *Intent) GetIntentStatus(ctx echo.Context, intentId string) error {
+	user := ctx.Get("user").(authenticationv1.UserInfo)
 
-		switch {
-		case body.Intent.Workload.Resources.Gpu.Tier == nil, *body.Intent.Workload.Resources.Gpu.Tier == "any":
-			intent.Spec.Workload.Resources.GPU.Tier = "Any"
-		default:
-			intent.Spec.Workload.Resources.GPU.Tier = string(*body.Intent.Workload.Resources.Gpu.Tier)
+	tnt, notFoundErr := i.Helper.RetrieveCapsuleTenant(ctx.Request().Context(), user)
+	if notFoundErr != nil {
+		if apierrors.IsNotFound(notFoundErr) {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{
+				"error": "user is not assigned to any Tenant",
+			})
 		}
 
-		if body.Intent.Workload.Resources.Gpu.Topology != nil {
-			switch *body.Intent.Workload.Resources.Gpu.Topology {
-			case api.AllToAll:
-				intent.Spec.Workload.Resources.GPU.Topology = ptr.To("AllToAll")
-			case api.Mesh:
-				intent.Spec.Workload.Resources.GPU.Topology = ptr.To("Mesh")
-			case api.Nvswitch:
-				intent.Spec.Workload.Resources.GPU.Topology = ptr.To("Nvswitch")
-			case api.Ring:
-				intent.Spec.Workload.Resources.GPU.Topology = ptr.To("Ring")
-			default:
-				return ctx.JSON(http.StatusBadRequest, map[string]string{
-					"error":   "unhandled GPU topology enum",
-					"context": string(*body.Intent.Workload.Resources.Gpu.Topology),
-				})
-			}
-		}
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{
+			"error":   notFoundErr.Error(),
+			"context": "cannot retrieve list of Tenants",
+		})
 	}
 
-	if body.Intent.Workload.Resources.Memory != nil {
-		qty, qErr := resource.ParseQuantity(*body.Intent.Workload.Resources.Memory)
-		if qErr != nil {
-			return ctx.JSON(http.StatusBadRequest, map[string]string{
-				"error":   "cannot parse quantity for memory",
-				"context": *body.Intent.Workload.Resources.Memory,
-			})
-		}
+	var intentList flarev1alpha1.IntentList
+	if err := i.Client.List(ctx.Request().Context(), &intentList, client.MatchingFields(fields.Set{i.IntentUIDIndexer.Field(): intentId})); err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{
+			"error":   err.Error(),
+			"context": "cannot retrieve list of Intents by UID",
+		})
+	}
 
-		intent.Spec.Workload.Resources.Memory = qty
+	if len(intentList.Items) == 0 {
+		return ctx.JSON(http.StatusNotFound, map[string]string{
+			"err": "intent not found",
+		})
 	}
 
-	if body.Intent.Workload.Secrets != nil {
-		intent.Spec.Workload.Secrets = make([]flarev1alpha1.IntentWorkloadSecret, 0, len(*body.Intent.Workload.Secrets))
-		for _, secret := range *body.Intent.Workload.Secrets {
-			intent.Spec.Workload.Secrets = append(intent.Spec.Workload.Secrets, flarev1alpha1.IntentWorkloadSecret{
-				Name: secret.Name,
-				Env:  secret.Env,
+	namespaceList := sets.New[string](tnt.Status.Namespaces...)
+	for _, intent := range intentList.Items {
+		if !namespaceList.Has(intent.Namespace) {
+			return ctx.JSON(http.StatusNotFound, map[string]string{
+				"err": "intent not found",
 			})
 		}
-	}
 
-	if body.Intent.Workload.Storage != nil && *body.Intent.Workload.Storage.Volumes != nil {
-		intent.Spec.Workload.Storage.Volumes = make([]flarev1alpha1.IntentWorkloadStorageVolume, 0, len(*body.Intent.Workload.Storage.Volumes))
-		for _, volume := range *body.Intent.Workload.Storage.Volumes {
-			vol := flarev1alpha1.IntentWorkloadStorageVolume{
-				Name: volume.Name,
-				Path: volume.Path,
-			}
-
-			if qty, qErr := resource.ParseQuantity(volume.Size); qErr != nil {
-				return ctx.JSON(http.StatusBadRequest, map[string]string{
-					"error":   "cannot parse quantity for volume size",
-					"context": volume.Size,
-				})
-			} else {
-				vol.Size = qty
-			}
+		return ctx.JSON(200, i.formatIntentToAPI(intent))
+	}
 
-			switch volume.Type {
-			case api.Persistent:
-				vol.Type = "Persistent"
-			case api.Temporary:
-				vol.Type = "Temporary"
-			default:
-				return ctx.JSON(http.StatusBadRequest, map[string]string{
-					"error":   "unhandled volume type enum",
-					"context": string(volume.Type),
-				})
-			}
+	return ctx.JSON(http.StatusInternalServerError, map[string]string{
+		"err": "Intent is expected to be found",
+	})
+}
 
-			if volume.Source != nil {
-				vol.Source = flarev1alpha1.IntentWorkloadStorageVolumeSource{
-					Credentials: ptr.Deref(volume.Source.Credentials, ""),
-					Type:        string(ptr.Deref(volume.Source.Type, "")),
-					Uri:         ptr.Deref(volume.Source.Uri, ""),
-				}
-			}
+// UpdateIntent applies the partial submission as a JSON merge patch of the one the Intent has been submitted with:
+// the operator rolls out the workload changes in place, and migrates to a new allocation upon
+// resources or constraints changes.
+func (i *Intent) UpdateIntent(ctx echo.Context, intentId string) error {
+	user := ctx.Get("user").(authenticationv1.UserInfo)
 
-			intent.Spec.Workload.Storage.Volumes = append(intent.Spec.Workload.Storage.Volumes, vol)
-		}
+	data, err := io.ReadAll(ctx.Request().Body)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
 	}
 
-	if err := i.Client.Create(ctx.Request().Context(), &intent); err != nil {
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{
-			"error":   err.Error(),
-			"context": "cannot create Intent",
+	var body map[string]any
+	if err = json.Unmarshal(data, &body); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
 		})
 	}
 
-	return ctx.JSON(200, api.SubmitIntentResponse{
-		EstimatedCost:      nil,
-		EstimatedStartTime: nil,
-		IntentId:           ptr.To(string(intent.UID)),
-		Message:            ptr.To("Intent received and processing"),
-		Status:             ptr.To("Pending"),
-	})
-}
-
-//+kubebuilder:rbac:groups=nodecore.fluidos.eu,resources=solvers,verbs=delete
-
-func (i *Intent) CancelIntent(ctx echo.Context, intentId string) error {
-	user := ctx.Get("user").(authenticationv1.UserInfo)
+	if body["intent"] == nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{
+			"error": "missing intent key in body",
+		})
+	}
+	// The partial intent is kept as raw JSON, since null values remove the submitted keys.
+	patch, err := json.Marshal(body["intent"])
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
 
 	tnt, notFoundErr := i.Helper.RetrieveCapsuleTenant(ctx.Request().Context(), user)
 	if notFoundErr != nil {
@@ -738,61 +556,139 @@ func (i *Intent) CancelIntent(ctx echo.Context, intentId string) error {
 	}
 
 	var intentList flarev1alpha1.IntentList
-	if err := i.Client.List(ctx.Request().Context(), &intentList, client.MatchingFields(fields.Set{i.IntentUIDIndexer.Field(): intentId})); err != nil {
+	if err = i.Client.List(ctx.Request().Context(), &intentList, client.MatchingFields(fields.Set{i.IntentUIDIndexer.Field(): intentId})); err != nil {
 		return ctx.JSON(http.StatusInternalServerError, map[string]string{
 			"error":   err.Error(),
 			"context": "cannot retrieve list of Intents by UID",
 		})
 	}
 
-	if len(intentList.Items) == 0 {
+	if len(intentList.Items) == 0 || !sets.New[string](tnt.Status.Namespaces...).Has(intentList.Items[0].Namespace) {
 		return ctx.JSON(http.StatusNotFound, map[string]string{
 			"err": "intent not found",
 		})
 	}
 
-	namespaceList := sets.New[string](tnt.Status.Namespaces...)
-	for _, intent := range intentList.Items {
-		if !namespaceList.Has(intent.Namespace) {
-			return ctx.JSON(http.StatusNotFound, map[string]string{
-				"err": "intent not found",
-			})
-		}
+	intent := intentList.Items[0]
 
-		if err := i.Client.Delete(ctx.Request().Context(), &intent); err != nil {
-			return ctx.JSON(http.StatusInternalServerError, map[string]string{
-				"error":   err.Error(),
-				"context": "cannot delete Intent",
-			})
-		}
+	if intent.DeletionTimestamp != nil {
+		return ctx.JSON(http.StatusConflict, map[string]string{
+			"error": "intent is terminating",
+		})
+	}
+
+	submission, ok := intent.Annotations[flarev1alpha1.IntentSubmissionAnnotation]
+	if !ok {
+		return ctx.JSON(http.StatusConflict, map[string]string{
+			"error": "intent has been submitted with no tracking of the submission, thus it cannot be updated",
+		})
+	}
 
-		var ns corev1.Namespace
-		ns.Name = intent.Namespace
-		if err := i.Client.Delete(ctx.Request().Context(), &ns); err != nil {
+	merged, err := jsonpatch.MergePatch([]byte(submission), patch)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{
+			"error":   err.Error(),
+			"context": "cannot apply the partial intent",
+		})
+	}
+
+	var in api.Intent
+	if err = json.Unmarshal(merged, &in); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{
+			"error":   err.Error(),
+			"context": "cannot decode the updated intent",
+		})
+	}
+
+	spec, specErr := intentSpecFromAPI(&in)
+	if specErr != nil {
+		return specErr.reply(ctx)
+	}
+
+	inline := inlineSecrets(&in)
+
+	if spec.Workload.Type != intent.Spec.Workload.Type {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{
+			"error":   "workload type cannot be changed",
+			"context": string(intent.Spec.Workload.Type),
+		})
+	}
+
+	if specErr = validateSecretValues(ctx.Request().Context(), i.Client, &intent, spec, inline); specErr != nil {
+		return specErr.reply(ctx)
+	}
+
+	if specErr = validateSecretReferences(ctx.Request().Context(), i.Client, tnt.Name, spec); specErr != nil {
+		return specErr.reply(ctx)
+	}
+
+	if specErr = i.validateDomains(ctx.Request().Context(), spec, intent.UID); specErr != nil {
+		return specErr.reply(ctx)
+	}
+
+	// The inline values must not be tracked in the submission.
+	if len(inline) > 0 {
+		if merged, err = json.Marshal(in); err != nil {
 			return ctx.JSON(http.StatusInternalServerError, map[string]string{
 				"error":   err.Error(),
-				"context": "cannot delete Namespace",
+				"context": "cannot encode the updated intent",
 			})
 		}
+	}
 
-		var solver fluidosnodev1alpha1.Solver
-		solver.Name = intent.Namespace
-		solver.Namespace = flags.FluidosNamespace
+	intent.Spec = spec
+	intent.Annotations[flarev1alpha1.IntentSubmissionAnnotation] = string(merged)
 
-		if err := i.Client.Delete(ctx.Request().Context(), &solver); err != nil && !apierrors.IsNotFound(err) {
-			return ctx.JSON(http.StatusInternalServerError, map[string]string{
+	if err = i.Client.Update(ctx.Request().Context(), &intent); err != nil {
+		if apierrors.IsConflict(err) {
+			return ctx.JSON(http.StatusConflict, map[string]string{
 				"error":   err.Error(),
-				"context": "cannot delete Solver",
+				"context": "intent has been modified concurrently",
 			})
 		}
+
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{
+			"error":   err.Error(),
+			"context": "cannot update Intent",
+		})
+	}
+	// Stored once the update succeeded, the values are left untouched upon a rejected one.
+	if err = storeInlineSecrets(ctx.Request().Context(), i.Client, &intent, inline); err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{
+			"error":   err.Error(),
+			"context": "intent has been updated, but cannot store inline secrets",
+		})
 	}
 
-	return ctx.JSON(http.StatusAccepted, nil)
+	return ctx.JSON(200, i.formatIntentToAPI(intent))
 }
 
-func (i *Intent) GetIntentStatus(ctx echo.Context, intentId string) error {
+// ResumeIntent raises the maximum total cost of the Intent beyond the accrued one:
+// the operator resumes the workload suspended upon the budget exhaustion.
+func (i *Intent) ResumeIntent(ctx echo.Context, intentId string) error {
 	user := ctx.Get("user").(authenticationv1.UserInfo)
 
+	var body api.ResumeIntentRequest
+	if err := ctx.Bind(&body); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	if body.MaxTotalCost == nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{
+			"error": "missing max_total_cost key in body",
+		})
+	}
+
+	maxTotalCost, err := strconv.ParseFloat(strings.TrimSpace(strings.ReplaceAll(*body.MaxTotalCost, "EUR", "")), 64)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{
+			"error":   err.Error(),
+			"context": "cannot parse max_total_cost",
+		})
+	}
+
 	tnt, notFoundErr := i.Helper.RetrieveCapsuleTenant(ctx.Request().Context(), user)
 	if notFoundErr != nil {
 		if apierrors.IsNotFound(notFoundErr) {
@@ -808,33 +704,75 @@ func (i *Intent) GetIntentStatus(ctx echo.Context, intentId string) error {
 	}
 
 	var intentList flarev1alpha1.IntentList
-	if err := i.Client.List(ctx.Request().Context(), &intentList, client.MatchingFields(fields.Set{i.IntentUIDIndexer.Field(): intentId})); err != nil {
+	if err = i.Client.List(ctx.Request().Context(), &intentList, client.MatchingFields(fields.Set{i.IntentUIDIndexer.Field(): intentId})); err != nil {
 		return ctx.JSON(http.StatusInternalServerError, map[string]string{
 			"error":   err.Error(),
 			"context": "cannot retrieve list of Intents by UID",
 		})
 	}
 
-	if len(intentList.Items) == 0 {
+	if len(intentList.Items) == 0 || !sets.New[string](tnt.Status.Namespaces...).Has(intentList.Items[0].Namespace) {
 		return ctx.JSON(http.StatusNotFound, map[string]string{
 			"err": "intent not found",
 		})
 	}
 
-	namespaceList := sets.New[string](tnt.Status.Namespaces...)
-	for _, intent := range intentList.Items {
-		if !namespaceList.Has(intent.Namespace) {
-			return ctx.JSON(http.StatusNotFound, map[string]string{
-				"err": "intent not found",
+	intent := intentList.Items[0]
+
+	if intent.DeletionTimestamp != nil {
+		return ctx.JSON(http.StatusConflict, map[string]string{
+			"error": "intent is terminating",
+		})
+	}
+
+	if accrued := intent.Status.Cost.Accrued; maxTotalCost <= accrued {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{
+			"error":   "max_total_cost must exceed the accrued cost",
+			"context": strconv.FormatFloat(accrued, 'f', 2, 64) + " EUR",
+		})
+	}
+
+	intent.Spec.Constraints.MaxTotalCost = maxTotalCost
+	// Keeping the submission in sync, since it's the base document of the following partial updates.
+	if submission, ok := intent.Annotations[flarev1alpha1.IntentSubmissionAnnotation]; ok {
+		patch, patchErr := json.Marshal(map[string]any{
+			"constraints": map[string]any{
+				"max_total_cost": *body.MaxTotalCost,
+			},
+		})
+		if patchErr != nil {
+			return ctx.JSON(http.StatusInternalServerError, map[string]string{
+				"error":   patchErr.Error(),
+				"context": "cannot encode the budget patch",
 			})
 		}
 
-		return ctx.JSON(200, i.formatIntentToAPI(intent))
+		merged, mergeErr := jsonpatch.MergePatch([]byte(submission), patch)
+		if mergeErr != nil {
+			return ctx.JSON(http.StatusInternalServerError, map[string]string{
+				"error":   mergeErr.Error(),
+				"context": "cannot apply the budget to the submitted intent",
+			})
+		}
+
+		intent.Annotations[flarev1alpha1.IntentSubmissionAnnotation] = string(merged)
 	}
 
-	return ctx.JSON(http.StatusInternalServerError, map[string]string{
-		"err": "Intent is expected to be found",
-	})
+	if err = i.Client.Update(ctx.Request().Context(), &intent); err != nil {
+		if apierrors.IsConflict(err) {
+			return ctx.JSON(http.StatusConflict, map[string]string{
+				"error":   err.Error(),
+				"context": "intent has been modified concurrently",
+			})
+		}
+
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{
+			"error":   err.Error(),
+			"context": "cannot update Intent",
+		})
+	}
+
+	return ctx.JSON(200, i.formatIntentToAPI(intent))
 }
 
 func (i *Intent) GetAvailableResources(ctx echo.Context) error {
@@ -850,17 +788,17 @@ func (i *Intent) GetAvailableResources(ctx echo.Context) error {
 	availableGPUs := make([]api.AvailableGPU, 0, len(peeringCandidates.Items))
 
 	for _, pc := range peeringCandidates.Items {
-		var obj map[string]interface{}
-		if err := json.Unmarshal(pc.Spec.Flavor.Spec.FlavorType.TypeData.Raw, &obj); err != nil {
+		gpu, ok := flavor.GPUCharacteristics(pc.Spec.Flavor)
+		if !ok {
 			continue
 		}
 
-		costPerHour, _, _ := unstructured.NestedFloat64(obj, "characteristics", "gpu", "hourly_rate")
-		count, _, _ := unstructured.NestedInt64(obj, "characteristics", "gpu", "count")
-		memory, _, _ := unstructured.NestedString(obj, "characteristics", "gpu", "memory")
-		model, _, _ := unstructured.NestedString(obj, "characteristics", "gpu", "model")
-		provider, _, _ := unstructured.NestedString(obj, "characteristics", "gpu", "provider")
-		region, _, _ := unstructured.NestedString(obj, "characteristics", "gpu", "region")
+		costPerHour, _ := flavor.HourlyRate(pc.Spec.Flavor)
+		count, _, _ := unstructured.NestedInt64(gpu, "count")
+		memory, _, _ := unstructured.NestedString(gpu, "memory")
+		model, _, _ := unstructured.NestedString(gpu, "model")
+		provider, _, _ := unstructured.NestedString(gpu, "provider")
+		region, _, _ := unstructured.NestedString(gpu, "region")
 		availableGPUs = append(availableGPUs, api.AvailableGPU{
 			CostPerHour: ptr.To(strconv.FormatFloat(costPerHour, 'g', -1, 64) + " EUR"),
 			Count:       ptr.To(int(count)),
@@ -875,3 +813,48 @@ func (i *Intent) GetAvailableResources(ctx echo.Context) error {
 		AvailableGpus: &availableGPUs,
 	})
 }
+
+// estimateCost returns the lowest hourly rate among the available PeeringCandidates
+// satisfying the GPU requirements of the Intent, nil when none is matching.
+func (i *Intent) estimateCost(ctx context.Context, spec flarev1alpha1.IntentSpec) *string {
+	var peeringCandidates fluidosv1alpha1.PeeringCandidateList
+	if err := i.Client.List(ctx, &peeringCandidates); err != nil {
+		return nil
+	}
+
+	var estimate *float64
+
+	for _, pc := range peeringCandidates.Items {
+		if !pc.Spec.Available {
+			continue
+		}
+
+		gpu, ok := flavor.GPUCharacteristics(pc.Spec.Flavor)
+		if !ok {
+			continue
+		}
+
+		if count, _, _ := unstructured.NestedInt64(gpu, "count"); count < spec.Workload.Resources.GPU.Count {
+			continue
+		}
+
+		if model, _, _ := unstructured.NestedString(gpu, "model"); spec.Workload.Resources.GPU.Model != "" && spec.Workload.Resources.GPU.Model != "Any" && !strings.EqualFold(model, spec.Workload.Resources.GPU.Model) {
+			continue
+		}
+
+		rate, ok := flavor.HourlyRate(pc.Spec.Flavor)
+		if !ok {
+			continue
+		}
+
+		if estimate == nil || rate < *estimate {
+			estimate = ptr.To(rate)
+		}
+	}
+
+	if estimate == nil {
+		return nil
+	}
+
+	return ptr.To(strconv.FormatFloat(*estimate, 'f', 2, 64) + " EUR/hour")
+}
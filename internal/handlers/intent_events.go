@@ -0,0 +1,203 @@
+// Copyright 2025 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/sets"
+	toolscache "k8s.io/client-go/tools/cache"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	flarev1alpha1 "github.com/clastix/flare-internal/api/v1alpha1"
+	"github.com/clastix/flare-internal/internal/api"
+)
+
+// intentEventsKeepAlive is the interval a comment is sent at when no event occurs,
+// preventing proxies from closing the idle stream.
+const intentEventsKeepAlive = 30 * time.Second
+
+// intentEvent is a Server-Sent Event of the stream, named after its kind.
+type intentEvent struct {
+	name string
+	data api.IntentEvent
+}
+
+// StreamIntentEvents streams the transitions of the Intent conditions as Server-Sent Events,
+// relying on the informer of the cache rather than polling the API Server:
+// the current conditions are sent first, and the stream ends once the Intent is deleted.
+func (i *Intent) StreamIntentEvents(ctx echo.Context, intentId string) error {
+	user := ctx.Get("user").(authenticationv1.UserInfo)
+
+	tnt, notFoundErr := i.Helper.RetrieveCapsuleTenant(ctx.Request().Context(), user)
+	if notFoundErr != nil {
+		if apierrors.IsNotFound(notFoundErr) {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{
+				"error": "user is not assigned to any Tenant",
+			})
+		}
+
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{
+			"error":   notFoundErr.Error(),
+			"context": "cannot retrieve list of Tenants",
+		})
+	}
+
+	var intentList flarev1alpha1.IntentList
+	if err := i.Client.List(ctx.Request().Context(), &intentList, client.MatchingFields(fields.Set{i.IntentUIDIndexer.Field(): intentId})); err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{
+			"error":   err.Error(),
+			"context": "cannot retrieve list of Intents by UID",
+		})
+	}
+
+	namespaceList := sets.New[string](tnt.Status.Namespaces...)
+	if len(intentList.Items) == 0 || !namespaceList.Has(intentList.Items[0].Namespace) {
+		return ctx.JSON(http.StatusNotFound, map[string]string{
+			"err": "intent not found",
+		})
+	}
+
+	informer, err := i.Informers.GetInformer(ctx.Request().Context(), &flarev1alpha1.Intent{})
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{
+			"error":   err.Error(),
+			"context": "cannot retrieve Intent informer",
+		})
+	}
+
+	done := ctx.Request().Context().Done()
+	events := make(chan intentEvent)
+
+	send := func(batch []intentEvent) {
+		for _, event := range batch {
+			select {
+			case events <- event:
+			case <-done:
+				return
+			}
+		}
+	}
+	// The informer replays the cached Intent upon the handler registration, delivering the current conditions
+	// with no gap between them and the following transitions.
+	registration, err := informer.AddEventHandler(toolscache.FilteringResourceEventHandler{
+		FilterFunc: func(obj interface{}) bool {
+			if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+
+			intent, ok := obj.(*flarev1alpha1.Intent)
+
+			return ok && string(intent.UID) == intentId
+		},
+		Handler: toolscache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				intent := obj.(*flarev1alpha1.Intent)
+
+				send(i.conditionEvents(*intent, nil))
+			},
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				oldIntent, newIntent := oldObj.(*flarev1alpha1.Intent), newObj.(*flarev1alpha1.Intent)
+
+				send(i.conditionEvents(*newIntent, oldIntent.Status.Conditions))
+			},
+			DeleteFunc: func(interface{}) {
+				send([]intentEvent{{
+					name: "deleted",
+					data: api.IntentEvent{
+						IntentStatus: ptr.To("Deleted"),
+						Timestamp:    ptr.To(time.Now()),
+					},
+				}})
+			},
+		},
+	})
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{
+			"error":   err.Error(),
+			"context": "cannot watch Intent",
+		})
+	}
+
+	defer func() {
+		_ = informer.RemoveEventHandler(registration)
+	}()
+
+	res := ctx.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	ticker := time.NewTicker(intentEventsKeepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return nil
+		case <-ticker.C:
+			if _, err = fmt.Fprint(res, ": keep-alive\n\n"); err != nil {
+				return nil
+			}
+		case event := <-events:
+			data, jsonErr := json.Marshal(event.data)
+			if jsonErr != nil {
+				return nil
+			}
+
+			if _, err = fmt.Fprintf(res, "event: %s\ndata: %s\n\n", event.name, data); err != nil {
+				return nil
+			}
+
+			if event.name == "deleted" {
+				res.Flush()
+
+				return nil
+			}
+		}
+
+		res.Flush()
+	}
+}
+
+// conditionEvents returns the events of the Intent conditions transitioned since the previous ones:
+// a condition transitions when it's added, or its status, reason or message change.
+func (i *Intent) conditionEvents(intent flarev1alpha1.Intent, previous []metav1.Condition) []intentEvent {
+	status := i.formatIntentToAPI(intent).Status
+
+	var events []intentEvent
+
+	for _, condition := range intent.Status.Conditions {
+		if old := meta.FindStatusCondition(previous, condition.Type); old != nil &&
+			old.Status == condition.Status && old.Reason == condition.Reason && old.Message == condition.Message {
+			continue
+		}
+
+		events = append(events, intentEvent{
+			name: "condition",
+			data: api.IntentEvent{
+				Condition:    ptr.To(condition.Type),
+				IntentStatus: status,
+				Message:      ptr.To(condition.Message),
+				Reason:       ptr.To(condition.Reason),
+				Status:       ptr.To(string(condition.Status)),
+				Timestamp:    ptr.To(condition.LastTransitionTime.Time),
+			},
+		})
+	}
+
+	return events
+}
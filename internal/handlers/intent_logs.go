@@ -0,0 +1,211 @@
+// Copyright 2025 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	flarev1alpha1 "github.com/clastix/flare-internal/api/v1alpha1"
+	"github.com/clastix/flare-internal/internal/api"
+)
+
+//+kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=pods/log,verbs=get
+
+// GetIntentLogs streams the logs of the workload Pods through the API Server, since Liqo reflects the logs
+// of the offloaded Pods from the provider cluster: the lines of different Pods are prefixed with the Pod name.
+func (i *Intent) GetIntentLogs(ctx echo.Context, intentId string, params api.GetIntentLogsParams) error {
+	user := ctx.Get("user").(authenticationv1.UserInfo)
+
+	if params.TailLines != nil && *params.TailLines < 0 {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tail_lines must be a non-negative integer",
+		})
+	}
+
+	tnt, notFoundErr := i.Helper.RetrieveCapsuleTenant(ctx.Request().Context(), user)
+	if notFoundErr != nil {
+		if apierrors.IsNotFound(notFoundErr) {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{
+				"error": "user is not assigned to any Tenant",
+			})
+		}
+
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{
+			"error":   notFoundErr.Error(),
+			"context": "cannot retrieve list of Tenants",
+		})
+	}
+
+	var intentList flarev1alpha1.IntentList
+	if err := i.Client.List(ctx.Request().Context(), &intentList, client.MatchingFields(fields.Set{i.IntentUIDIndexer.Field(): intentId})); err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{
+			"error":   err.Error(),
+			"context": "cannot retrieve list of Intents by UID",
+		})
+	}
+
+	namespaceList := sets.New[string](tnt.Status.Namespaces...)
+	if len(intentList.Items) == 0 || !namespaceList.Has(intentList.Items[0].Namespace) {
+		return ctx.JSON(http.StatusNotFound, map[string]string{
+			"err": "intent not found",
+		})
+	}
+
+	intent := intentList.Items[0]
+	// Pods are listed with no cache, sparing the server from caching the Pods of the whole cluster.
+	podList, err := i.Clientset.CoreV1().Pods(intent.Namespace).List(ctx.Request().Context(), metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{"intent": intent.Name}).String(),
+	})
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{
+			"error":   err.Error(),
+			"context": "cannot retrieve list of Pods",
+		})
+	}
+
+	if len(podList.Items) == 0 {
+		return ctx.JSON(http.StatusNotFound, map[string]string{
+			"error":   "workload has no Pods",
+			"context": "the intent is not running",
+		})
+	}
+
+	slices.SortFunc(podList.Items, func(a, b corev1.Pod) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	options := &corev1.PodLogOptions{
+		Container: intent.Spec.Workload.Name,
+		Follow:    ptr.Deref(params.Follow, false),
+	}
+
+	if params.Container != nil {
+		options.Container = *params.Container
+	}
+
+	if params.TailLines != nil {
+		options.TailLines = ptr.To(int64(*params.TailLines))
+	}
+
+	if params.SinceTime != nil {
+		options.SinceTime = ptr.To(metav1.NewTime(*params.SinceTime))
+	}
+	// Opening the streams before writing the response, for the errors to be reported with the proper status code.
+	streams := make(map[string]io.ReadCloser, len(podList.Items))
+
+	defer func() {
+		for _, stream := range streams {
+			_ = stream.Close()
+		}
+	}()
+
+	for _, pod := range podList.Items {
+		stream, streamErr := i.Clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, options).Stream(ctx.Request().Context())
+		if streamErr != nil {
+			if apierrors.IsBadRequest(streamErr) {
+				return ctx.JSON(http.StatusBadRequest, map[string]string{
+					"error":   streamErr.Error(),
+					"context": pod.Name,
+				})
+			}
+
+			return ctx.JSON(http.StatusInternalServerError, map[string]string{
+				"error":   streamErr.Error(),
+				"context": "cannot stream logs of Pod " + pod.Name,
+			})
+		}
+
+		streams[pod.Name] = stream
+	}
+
+	res := ctx.Response()
+	res.Header().Set(echo.HeaderContentType, echo.MIMETextPlainCharsetUTF8)
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	lines := make(chan string)
+
+	var wg sync.WaitGroup
+
+	for name, stream := range streams {
+		prefix := ""
+		if len(streams) > 1 {
+			prefix = "[" + name + "] "
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			readLogLines(ctx.Request().Context(), stream, prefix, lines)
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(lines)
+	}()
+
+	for line := range lines {
+		if _, err = io.WriteString(res, line); err != nil {
+			break
+		}
+
+		res.Flush()
+	}
+	// Draining the lines of the readers still running, stopped by the closed streams.
+	for _, stream := range streams {
+		_ = stream.Close()
+	}
+
+	for range lines {
+	}
+
+	return nil
+}
+
+// readLogLines sends the log lines of the stream, each one with the given prefix,
+// until the stream ends or the request is canceled.
+func readLogLines(ctx context.Context, stream io.Reader, prefix string, lines chan<- string) {
+	reader := bufio.NewReader(stream)
+
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			if !strings.HasSuffix(line, "\n") {
+				line += "\n"
+			}
+
+			select {
+			case lines <- prefix + line:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
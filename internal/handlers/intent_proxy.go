@@ -0,0 +1,230 @@
+// Copyright 2025 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package handlers
+
+import (
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	flarev1alpha1 "github.com/clastix/flare-internal/api/v1alpha1"
+	"github.com/clastix/flare-internal/internal/api"
+)
+
+//+kubebuilder:rbac:groups="",resources=pods/exec;pods/portforward,verbs=get;create
+
+// ExecIntent proxies the remote command stream of the command executed in a workload Pod.
+func (i *Intent) ExecIntent(ctx echo.Context, intentId string, params api.ExecIntentParams) error {
+	if len(params.Command) == 0 {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{
+			"error": "missing command",
+		})
+	}
+
+	tty := ptr.Deref(params.Tty, false)
+
+	query := url.Values{
+		"command": params.Command,
+		"stdin":   []string{strconv.FormatBool(ptr.Deref(params.Stdin, false))},
+		"stdout":  []string{"true"},
+		// The standard error is merged with the standard output when a terminal is allocated.
+		"stderr": []string{strconv.FormatBool(!tty)},
+		"tty":    []string{strconv.FormatBool(tty)},
+	}
+
+	if params.Container != nil {
+		query.Set("container", *params.Container)
+	}
+
+	return i.proxyPodStream(ctx, intentId, params.Pod, "exec", query)
+}
+
+// PortForwardIntent proxies the port forward stream of the ports of a workload Pod.
+func (i *Intent) PortForwardIntent(ctx echo.Context, intentId string, params api.PortForwardIntentParams) error {
+	if len(params.Ports) == 0 {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{
+			"error": "missing ports",
+		})
+	}
+
+	query := url.Values{}
+
+	for _, port := range params.Ports {
+		if port < 1 || port > 65535 {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{
+				"error":   "invalid port",
+				"context": strconv.Itoa(port),
+			})
+		}
+
+		query.Add("ports", strconv.Itoa(port))
+	}
+
+	return i.proxyPodStream(ctx, intentId, params.Pod, "portforward", query)
+}
+
+// proxyPodStream proxies the upgraded connection to the streaming subresource of the workload Pod,
+// relaying the WebSocket or SPDY stream negotiated by the client with the API Server: the connection
+// is authenticated with the server credentials, the token being granted the intents:exec permission.
+func (i *Intent) proxyPodStream(ctx echo.Context, intentId string, podName *string, subresource string, query url.Values) error {
+	user := ctx.Get("user").(authenticationv1.UserInfo)
+
+	if ctx.Request().Header.Get("Upgrade") == "" {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{
+			"error": "connection must be upgraded to a WebSocket or SPDY stream",
+		})
+	}
+
+	tnt, notFoundErr := i.Helper.RetrieveCapsuleTenant(ctx.Request().Context(), user)
+	if notFoundErr != nil {
+		if apierrors.IsNotFound(notFoundErr) {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{
+				"error": "user is not assigned to any Tenant",
+			})
+		}
+
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{
+			"error":   notFoundErr.Error(),
+			"context": "cannot retrieve list of Tenants",
+		})
+	}
+
+	var intentList flarev1alpha1.IntentList
+	if err := i.Client.List(ctx.Request().Context(), &intentList, client.MatchingFields(fields.Set{i.IntentUIDIndexer.Field(): intentId})); err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{
+			"error":   err.Error(),
+			"context": "cannot retrieve list of Intents by UID",
+		})
+	}
+
+	namespaceList := sets.New[string](tnt.Status.Namespaces...)
+	if len(intentList.Items) == 0 || !namespaceList.Has(intentList.Items[0].Namespace) {
+		return ctx.JSON(http.StatusNotFound, map[string]string{
+			"err": "intent not found",
+		})
+	}
+
+	intent := intentList.Items[0]
+
+	podList, err := i.Clientset.CoreV1().Pods(intent.Namespace).List(ctx.Request().Context(), metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{"intent": intent.Name}).String(),
+	})
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{
+			"error":   err.Error(),
+			"context": "cannot retrieve list of Pods",
+		})
+	}
+
+	slices.SortFunc(podList.Items, func(a, b corev1.Pod) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	// Only the Pods of the workload can be reached, defaulting to the first running one.
+	index := slices.IndexFunc(podList.Items, func(pod corev1.Pod) bool {
+		if podName != nil {
+			return pod.Name == *podName
+		}
+
+		return pod.Status.Phase == corev1.PodRunning
+	})
+	if index < 0 {
+		return ctx.JSON(http.StatusNotFound, map[string]string{
+			"error":   "workload has no such running Pod",
+			"context": ptr.Deref(podName, ""),
+		})
+	}
+
+	if !query.Has("container") && subresource == "exec" {
+		query.Set("container", intent.Spec.Workload.Name)
+	}
+
+	target, _, err := rest.DefaultServerUrlFor(i.RESTConfig)
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{
+			"error":   err.Error(),
+			"context": "cannot retrieve API Server URL",
+		})
+	}
+
+	transport, err := podStreamTransport(i.RESTConfig)
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{
+			"error":   err.Error(),
+			"context": "cannot initialize API Server transport",
+		})
+	}
+
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.Out.URL.Scheme = target.Scheme
+			r.Out.URL.Host = target.Host
+			r.Out.URL.Path = path.Join(target.Path, "/api/v1/namespaces", intent.Namespace, "pods", podList.Items[index].Name, subresource)
+			r.Out.URL.RawQuery = query.Encode()
+			r.Out.Host = target.Host
+			// The transport authenticates with the server credentials: only the headers negotiating the stream
+			// reach the API Server, neither the FLARE token nor the impersonation and the authenticating proxy ones.
+			r.Out.Header = podStreamHeaders(r.In.Header)
+		},
+		Transport: transport,
+		ErrorHandler: func(_ http.ResponseWriter, _ *http.Request, err error) {
+			_ = ctx.JSON(http.StatusBadGateway, map[string]string{
+				"error":   err.Error(),
+				"context": "cannot proxy the " + subresource + " stream",
+			})
+		},
+	}
+
+	proxy.ServeHTTP(ctx.Response(), ctx.Request())
+
+	return nil
+}
+
+// podStreamHeaders returns the request headers negotiating the upgraded connection to the API Server.
+func podStreamHeaders(in http.Header) http.Header {
+	out := http.Header{}
+
+	for key, values := range in {
+		canonical := http.CanonicalHeaderKey(key)
+
+		if canonical == "Upgrade" || canonical == "Connection" || canonical == "X-Stream-Protocol-Version" || strings.HasPrefix(canonical, "Sec-Websocket-") {
+			out[canonical] = slices.Clone(values)
+		}
+	}
+
+	return out
+}
+
+// podStreamTransport returns the transport for the upgraded connections to the API Server:
+// HTTP/2 is not negotiated, since it doesn't support the connection upgrade.
+func podStreamTransport(config *rest.Config) (http.RoundTripper, error) {
+	tlsConfig, err := rest.TLSConfigFor(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if tlsConfig != nil {
+		tlsConfig.NextProtos = []string{"http/1.1"}
+	}
+
+	return rest.HTTPWrappersForConfig(config, &http.Transport{
+		Proxy:           http.ProxyFromEnvironment,
+		TLSClientConfig: tlsConfig,
+	})
+}
@@ -0,0 +1,690 @@
+// Copyright 2025 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/utils/ptr"
+
+	flarev1alpha1 "github.com/clastix/flare-internal/api/v1alpha1"
+	"github.com/clastix/flare-internal/internal/api"
+	"github.com/clastix/flare-internal/internal/flavor"
+)
+
+// intentSpecError describes why the submitted Intent cannot be translated, replied as it is to the client.
+type intentSpecError struct {
+	code    int
+	err     string
+	context string
+}
+
+func (e *intentSpecError) reply(ctx echo.Context) error {
+	return ctx.JSON(e.code, map[string]string{
+		"error":   e.err,
+		"context": e.context,
+	})
+}
+
+// intentSpecFromAPI translates the submitted Intent into the Intent specification.
+func intentSpecFromAPI(in *api.Intent) (flarev1alpha1.IntentSpec, *intentSpecError) {
+	var spec flarev1alpha1.IntentSpec
+
+	if in.Constraints != nil {
+		if in.Constraints.Availability != nil {
+			if in.Constraints.Availability.BlackoutDates != nil {
+				spec.Constraints.Availability.BlackoutDates = make([]string, 0, len(*in.Constraints.Availability.BlackoutDates))
+				for _, date := range *in.Constraints.Availability.BlackoutDates {
+					spec.Constraints.Availability.BlackoutDates = append(spec.Constraints.Availability.BlackoutDates, date.String())
+				}
+			}
+
+			spec.Constraints.Availability.DaysOfWeek = make([]flarev1alpha1.DayOfWeek, 0, len(in.Constraints.Availability.DaysOfWeek))
+			for _, day := range in.Constraints.Availability.DaysOfWeek {
+				spec.Constraints.Availability.DaysOfWeek = append(spec.Constraints.Availability.DaysOfWeek, flarev1alpha1.DayOfWeek(day))
+			}
+
+			spec.Constraints.Availability.MaintenanceWindows = make([]flarev1alpha1.IntentConstraintAvailabilityMaintenanceWindow, 0, len(in.Constraints.Availability.MaintenanceWindows))
+			for _, window := range in.Constraints.Availability.MaintenanceWindows {
+				var maintenanceWindow flarev1alpha1.IntentConstraintAvailabilityMaintenanceWindow
+
+				switch {
+				case window.Frequency == nil:
+					break
+				case *window.Frequency == api.Monthly:
+					maintenanceWindow.Frequency = "Monthly"
+				case *window.Frequency == api.Weekly:
+					maintenanceWindow.Frequency = "Weekly"
+				default:
+					return spec, &intentSpecError{
+						code:    http.StatusBadRequest,
+						err:     "unhandled frequency enum",
+						context: string(*window.Frequency),
+					}
+				}
+
+				maintenanceWindow.Start = metav1.NewTime(window.Start)
+				maintenanceWindow.End = metav1.NewTime(window.End)
+
+				spec.Constraints.Availability.MaintenanceWindows = append(spec.Constraints.Availability.MaintenanceWindows, maintenanceWindow)
+			}
+
+			spec.Constraints.Availability.Timezone = in.Constraints.Availability.Timezone
+			spec.Constraints.Availability.WindowEnd = in.Constraints.Availability.WindowEnd
+			spec.Constraints.Availability.WindowStart = in.Constraints.Availability.WindowStart
+		}
+
+		if in.Constraints.AvailabilityZone != nil {
+			spec.Constraints.AvailabilityZone = *in.Constraints.AvailabilityZone
+		}
+
+		if in.Constraints.Compliance != nil {
+			spec.Constraints.Compliance.AuditLogging = ptr.Deref(in.Constraints.Compliance.AuditLogging, false)
+
+			if in.Constraints.Compliance.Certifications != nil {
+				spec.Constraints.Compliance.Certifications = make([]flarev1alpha1.Certification, 0, len(*in.Constraints.Compliance.Certifications))
+				for _, cert := range *in.Constraints.Compliance.Certifications {
+					switch cert {
+					case api.ISO27001, api.SOC2:
+						spec.Constraints.Compliance.Certifications = append(spec.Constraints.Compliance.Certifications, flarev1alpha1.Certification(cert))
+					default:
+						return spec, &intentSpecError{
+							code:    http.StatusBadRequest,
+							err:     "unhandled certification enum",
+							context: string(cert),
+						}
+					}
+				}
+			}
+
+			if in.Constraints.Compliance.DataResidency != nil {
+				spec.Constraints.Compliance.DataResidency = make([]string, 0, len(*in.Constraints.Compliance.DataResidency))
+				for _, dataResidency := range *in.Constraints.Compliance.DataResidency {
+					spec.Constraints.Compliance.DataResidency = append(spec.Constraints.Compliance.DataResidency, string(dataResidency))
+				}
+			}
+
+			spec.Constraints.Compliance.EncryptionAtRest = ptr.Deref(in.Constraints.Compliance.EncryptionAtRest, false)
+			spec.Constraints.Compliance.EncryptionInTransit = ptr.Deref(in.Constraints.Compliance.EncryptionInTransit, false)
+			spec.Constraints.Compliance.GDPRCompliant = ptr.Deref(in.Constraints.Compliance.GdprCompliant, false)
+			spec.Constraints.Compliance.HIPPACompliant = ptr.Deref(in.Constraints.Compliance.HipaaCompliant, false)
+		}
+
+		if in.Constraints.Deadline != nil {
+			spec.Constraints.Deadline = metav1.Time{Time: *in.Constraints.Deadline}
+		}
+
+		if in.Constraints.Energy != nil {
+			switch rating := strings.ToUpper(ptr.Deref(in.Constraints.Energy.EnergyEfficiencyRating, "")); rating {
+			case "", "A", "B", "C", "D", "E", "F":
+				spec.Constraints.Energy.EnergyEfficiencyRating = rating
+			default:
+				return spec, &intentSpecError{
+					code:    http.StatusBadRequest,
+					err:     "unhandled energy efficiency rating enum",
+					context: rating,
+				}
+			}
+
+			spec.Constraints.Energy.GreenCertifiedOnly = ptr.Deref(in.Constraints.Energy.GreenCertifiedOnly, false)
+
+			if in.Constraints.Energy.MaxCarbonFootprint != nil {
+				if _, err := flavor.ParseCarbonFootprint(*in.Constraints.Energy.MaxCarbonFootprint); err != nil {
+					return spec, &intentSpecError{
+						code:    http.StatusBadRequest,
+						err:     "cannot parse max carbon footprint value",
+						context: *in.Constraints.Energy.MaxCarbonFootprint,
+					}
+				}
+
+				spec.Constraints.Energy.MaxCarbonFootprint = *in.Constraints.Energy.MaxCarbonFootprint
+			}
+
+			spec.Constraints.Energy.PowerUsageEffectiveness = ptr.Deref(in.Constraints.Energy.PowerUsageEffectiveness, float32(0))
+			spec.Constraints.Energy.RenewableEnergyOnly = ptr.Deref(in.Constraints.Energy.RenewableEnergyOnly, false)
+		}
+
+		spec.Constraints.Location = ptr.Deref(in.Constraints.Location, "")
+		if in.Constraints.MaxHourlyCost != nil {
+			maxHourlyCost := strings.ReplaceAll(*in.Constraints.MaxHourlyCost, "EUR", "")
+			maxHourlyCost = strings.TrimSpace(maxHourlyCost)
+			spec.Constraints.MaxHourlyCost, _ = strconv.ParseFloat(maxHourlyCost, 32)
+		}
+
+		spec.Constraints.MaxLatencyMs = int64(ptr.Deref(in.Constraints.MaxLatencyMs, 0))
+
+		if in.Constraints.MaxTotalCost != nil {
+			maxTotalCost := strings.ReplaceAll(*in.Constraints.MaxTotalCost, "EUR", "")
+			maxTotalCost = strings.TrimSpace(maxTotalCost)
+			spec.Constraints.MaxTotalCost, _ = strconv.ParseFloat(maxTotalCost, 32)
+		}
+
+		if in.Constraints.Negotiation != nil {
+			spec.Constraints.Negotiation.AutoAcceptThreshold = float64(ptr.Deref(in.Constraints.Negotiation.AutoAcceptThreshold, float32(0)))
+
+			switch strategy := ptr.Deref(in.Constraints.Negotiation.FallbackStrategy, ""); strategy {
+			case "", "queue", "fail", "notify":
+				spec.Constraints.Negotiation.FallbackStrategy = strategy
+			default:
+				return spec, &intentSpecError{
+					code:    http.StatusBadRequest,
+					err:     "unhandled fallback strategy enum",
+					context: strategy,
+				}
+			}
+
+			spec.Constraints.Negotiation.MaxNegotiationRounds = ptr.Deref(in.Constraints.Negotiation.MaxNegotiationRounds, 0)
+			spec.Constraints.Negotiation.PriceFlexibility = float64(ptr.Deref(in.Constraints.Negotiation.PriceFlexibility, float32(0)))
+			spec.Constraints.Negotiation.ResourceFlexibility = float64(ptr.Deref(in.Constraints.Negotiation.ResourceFlexibility, float32(0)))
+			spec.Constraints.Negotiation.TimeoutSeconds = int64(ptr.Deref(in.Constraints.Negotiation.TimeoutSeconds, 0))
+		}
+
+		if in.Constraints.Performance != nil {
+			spec.Constraints.Performance.GpuUtilizationTarget = float64(ptr.Deref(in.Constraints.Performance.GpuUtilizationTarget, float32(0)))
+
+			if in.Constraints.Performance.MaxColdStartTime != nil {
+				if d, err := time.ParseDuration(*in.Constraints.Performance.MaxColdStartTime); err != nil {
+					return spec, &intentSpecError{
+						code:    http.StatusBadRequest,
+						err:     "cannot parse max cold start time value",
+						context: *in.Constraints.Performance.MaxColdStartTime,
+					}
+				} else {
+					spec.Constraints.Performance.MaxColdStartTime = metav1.Duration{Duration: d}
+				}
+			}
+
+			spec.Constraints.Performance.MaxJitterMs = int64(ptr.Deref(in.Constraints.Performance.MaxJitterMs, 0))
+			spec.Constraints.Performance.MemoryUtilizationTarget = float64(ptr.Deref(in.Constraints.Performance.MemoryUtilizationTarget, float32(0)))
+
+			if in.Constraints.Performance.MinNetworkBandwidth != nil {
+				qty, qErr := resource.ParseQuantity(*in.Constraints.Performance.MinNetworkBandwidth)
+				if qErr != nil {
+					return spec, &intentSpecError{
+						code:    http.StatusBadRequest,
+						err:     "cannot parse quantity for minimum network bandwidth",
+						context: *in.Constraints.Performance.MaxColdStartTime,
+					}
+				}
+
+				spec.Constraints.Performance.MinNetworkBandwidth = qty
+			}
+
+			spec.Constraints.Performance.MinUptimePercent = float64(ptr.Deref(in.Constraints.Performance.MinUptimePercent, float32(0)))
+		}
+
+		spec.Constraints.PreEmptible = ptr.Deref(in.Constraints.Preemptible, false)
+
+		if in.Constraints.Providers != nil {
+			spec.Constraints.Providers = make([]string, 0, len(*in.Constraints.Providers))
+			for _, provider := range *in.Constraints.Providers {
+				spec.Constraints.Providers = append(spec.Constraints.Providers, string(provider))
+			}
+		}
+
+		if in.Constraints.Security != nil {
+			spec.Constraints.Security.BastionHost = ptr.Deref(in.Constraints.Security.BastionHost, false)
+
+			if in.Constraints.Security.FirewallRules != nil {
+				spec.Constraints.Security.FirewallRules = make([]flarev1alpha1.IntentWorkloadConstraintSecurityFirewallRule, 0, len(*in.Constraints.Security.FirewallRules))
+
+				for _, rule := range *in.Constraints.Security.FirewallRules {
+					var r flarev1alpha1.IntentWorkloadConstraintSecurityFirewallRule
+
+					r.Port = int32(rule.Port)
+					r.Source = rule.Source
+					r.Protocol = rule.Protocol
+
+					switch rule.Action {
+					case api.Allow, api.Deny:
+						r.Action = string(rule.Action)
+					default:
+						return spec, &intentSpecError{
+							code:    http.StatusBadRequest,
+							err:     "unhandled action enum",
+							context: string(rule.Action),
+						}
+					}
+					spec.Constraints.Security.FirewallRules = append(spec.Constraints.Security.FirewallRules, r)
+				}
+			}
+
+			spec.Constraints.Security.IntrusionDetection = ptr.Deref(in.Constraints.Security.IntrusionDetection, false)
+
+			if in.Constraints.Security.NetworkIsolation != nil {
+				switch *in.Constraints.Security.NetworkIsolation {
+				case api.Private, api.Public:
+					spec.Constraints.Security.NetworkIsolation = string(*in.Constraints.Security.NetworkIsolation)
+				default:
+					return spec, &intentSpecError{
+						code:    http.StatusBadRequest,
+						err:     "unhandled network isolation enum",
+						context: string(*in.Constraints.Security.NetworkIsolation),
+					}
+				}
+
+			}
+
+			spec.Constraints.Security.VpnAccess = ptr.Deref(in.Constraints.Security.VpnAccess, false)
+			spec.Constraints.Security.VulnerabilityScanning = ptr.Deref(in.Constraints.Security.VulnerabilityScanning, false)
+		}
+	}
+
+	switch in.Objective {
+	case api.BalancedOptimization:
+		spec.Objective = flarev1alpha1.IntentObjectBalancedOptimization
+	case api.CostMinimization:
+		spec.Objective = flarev1alpha1.IntentObjectCostMinimization
+	case api.EnergyEfficiency:
+		spec.Objective = flarev1alpha1.IntentObjectEnergyEfficiency
+	case api.LatencyMinimization:
+		spec.Objective = flarev1alpha1.IntentObjectLatencyMinimization
+	case api.PerformanceMaximization:
+		spec.Objective = flarev1alpha1.IntentObjectPerformanceMaximization
+	default:
+		return spec, &intentSpecError{
+			code:    http.StatusInternalServerError,
+			err:     "unhandled objective type",
+			context: string(in.Objective),
+		}
+	}
+
+	if in.Sla != nil {
+		spec.SLA.Availability = ptr.Deref(in.Sla.Availability, "")
+		spec.SLA.BackupStrategy = ptr.Deref(in.Sla.BackupStrategy, "")
+
+		if in.Sla.MaxInterruptionTime != nil {
+			if d, err := time.ParseDuration(*in.Sla.MaxInterruptionTime); err != nil {
+				return spec, &intentSpecError{
+					code:    http.StatusBadRequest,
+					err:     "cannot parse max interruption time value",
+					context: *in.Sla.MaxInterruptionTime,
+				}
+			} else {
+				spec.SLA.MaxInterruptionTime = &metav1.Duration{Duration: d}
+			}
+		}
+	}
+
+	if in.Workload.Commands != nil {
+		spec.Workload.Commands = *in.Workload.Commands
+	}
+
+	if in.Workload.CommunicationPattern != nil {
+		switch *in.Workload.CommunicationPattern {
+		case api.AllReduce:
+			spec.Workload.CommunicationPattern = "AllReduce"
+		case api.Independent:
+			spec.Workload.CommunicationPattern = "Independent"
+		case api.Pipeline:
+			spec.Workload.CommunicationPattern = "Pipeline"
+		default:
+			return spec, &intentSpecError{
+				code:    http.StatusBadRequest,
+				err:     "unhandled communication pattern enum",
+				context: string(*in.Workload.CommunicationPattern),
+			}
+		}
+	}
+
+	if in.Workload.DeploymentStrategy != nil {
+		switch *in.Workload.DeploymentStrategy {
+		case api.Colocated:
+			spec.Workload.DeploymentStrategy = "Colocated"
+		case api.Distributed:
+			spec.Workload.DeploymentStrategy = "Distributed"
+		case api.Flexibile:
+			spec.Workload.DeploymentStrategy = "Flexible"
+		default:
+			return spec, &intentSpecError{
+				code:    http.StatusBadRequest,
+				err:     "unhandled deployment strategy enum",
+				context: string(*in.Workload.DeploymentStrategy),
+			}
+		}
+	}
+
+	if in.Workload.Env != nil {
+		spec.Workload.Env = append(spec.Workload.Env, *in.Workload.Env...)
+	}
+
+	spec.Workload.Image = in.Workload.Image
+	spec.Workload.Name = in.Workload.Name
+
+	switch in.Workload.Type {
+	case api.WorkloadTypeBatch:
+		spec.Workload.Type = flarev1alpha1.IntentWorkloadTypeBatch
+
+		if in.Workload.Batch != nil {
+			switch *in.Workload.Batch.CompletionPolicy {
+			case api.BatchCompletionPolicyAll, api.BatchCompletionPolicyAny:
+				spec.Workload.Batch.CompletionPolicy = string(*in.Workload.Batch.CompletionPolicy)
+			default:
+				return spec, &intentSpecError{
+					code:    http.StatusBadRequest,
+					err:     "unhandled completion policy enum",
+					context: string(*in.Workload.Batch.CompletionPolicy),
+				}
+			}
+
+			spec.Workload.Batch.MaxRetries = ptr.Deref(in.Workload.Batch.ParallelTasks, 3)
+			spec.Workload.Batch.ParallelTasks = ptr.Deref(in.Workload.Batch.ParallelTasks, 1)
+
+			if in.Workload.Batch.Timeout != nil {
+				if d, dErr := time.ParseDuration(*in.Workload.Batch.Timeout); dErr != nil {
+					return spec, &intentSpecError{
+						code:    http.StatusBadRequest,
+						err:     "cannot parse job timeout value",
+						context: *in.Workload.Batch.Timeout,
+					}
+				} else {
+					spec.Workload.Batch.Timeout = metav1.Duration{Duration: d}
+				}
+			}
+		}
+	case api.WorkloadTypeService:
+		spec.Workload.Type = flarev1alpha1.IntentWorkloadTypeService
+
+		if in.Workload.Scaling != nil {
+			spec.Workload.Scaling.AutoScale = ptr.Deref(in.Workload.Scaling.AutoScale, false)
+			spec.Workload.Scaling.MaxReplicas = ptr.Deref(in.Workload.Scaling.MaxReplicas, 10)
+			spec.Workload.Scaling.MinReplicas = ptr.Deref(in.Workload.Scaling.MinReplicas, 1)
+			spec.Workload.Scaling.TargetCpuPercent = ptr.Deref(in.Workload.Scaling.TargetCpuPercent, 70)
+			spec.Workload.Scaling.TargetGpuPercent = ptr.Deref(in.Workload.Scaling.TargetGpuPercent, 80)
+			// The HorizontalPodAutoscaler with no metrics would silently fall back to the default CPU target,
+			// and the utilization targets are computed upon the requested resources.
+			cpuTarget := spec.Workload.Scaling.TargetCpuPercent > 0 && in.Workload.Resources.Cpu != nil
+			gpuTarget := spec.Workload.Scaling.TargetGpuPercent > 0 && in.Workload.Resources.Gpu != nil && ptr.Deref(in.Workload.Resources.Gpu.Count, 0) > 0
+			if spec.Workload.Scaling.AutoScale && !cpuTarget && !gpuTarget {
+				return spec, &intentSpecError{
+					code:    http.StatusBadRequest,
+					err:     "autoscaling requires a CPU target along with CPU resources, or a GPU target along with GPU resources",
+					context: "scaling",
+				}
+			}
+		}
+	default:
+		return spec, &intentSpecError{
+			code:    http.StatusBadRequest,
+			err:     "unhandled workload type",
+			context: string(in.Workload.Type),
+		}
+	}
+
+	if in.Workload.Ports != nil {
+		domains := map[string]struct{}{}
+
+		for _, port := range *in.Workload.Ports {
+			if domain := ptr.Deref(port.Domain, ""); domain != "" {
+				if errs := validation.IsDNS1123Subdomain(domain); len(errs) > 0 {
+					return spec, &intentSpecError{
+						code:    http.StatusBadRequest,
+						err:     "invalid domain, " + strings.Join(errs, ", "),
+						context: domain,
+					}
+				}
+
+				if _, ok := domains[domain]; ok {
+					return spec, &intentSpecError{
+						code:    http.StatusBadRequest,
+						err:     "domain is assigned to multiple ports",
+						context: domain,
+					}
+				}
+
+				domains[domain] = struct{}{}
+			}
+
+			var appProtocol string
+
+			switch {
+			case port.AppProtocol == nil:
+				break
+			case *port.AppProtocol == api.Http:
+				appProtocol = "HTTP"
+			case *port.AppProtocol == api.Tcp:
+				appProtocol = "TCP"
+			default:
+				return spec, &intentSpecError{
+					code:    http.StatusBadRequest,
+					err:     "unhandled app_protocol enum",
+					context: string(*port.AppProtocol),
+				}
+			}
+
+			spec.Workload.Ports = append(spec.Workload.Ports, flarev1alpha1.IntentWorkloadPort{
+				Port: int32(port.Port),
+				Protocol: func() string {
+					if port.Protocol != nil {
+						return string(*port.Protocol)
+					}
+
+					return ""
+				}(),
+				AppProtocol: appProtocol,
+				Expose:      ptr.Deref(port.Expose, false),
+				Domain:      ptr.Deref(port.Domain, ""),
+			})
+		}
+	}
+
+	if in.Workload.Resources.Cpu != nil {
+		qty, qErr := resource.ParseQuantity(*in.Workload.Resources.Cpu)
+		if qErr != nil {
+			return spec, &intentSpecError{
+				code:    http.StatusBadRequest,
+				err:     "cannot parse quantity for CPU",
+				context: *in.Workload.Resources.Cpu,
+			}
+		}
+
+		spec.Workload.Resources.CPU = qty
+	}
+
+	if in.Workload.Resources.Gpu != nil {
+		switch {
+		case in.Workload.Resources.Gpu.Architecture == nil, *in.Workload.Resources.Gpu.Architecture == "any":
+			spec.Workload.Resources.GPU.Architecture = "Any"
+		default:
+			spec.Workload.Resources.GPU.Architecture = string(*in.Workload.Resources.Gpu.Architecture)
+		}
+
+		if in.Workload.Resources.Gpu.ClockSpeedMin != nil {
+			qty, qErr := resource.ParseQuantity(*in.Workload.Resources.Gpu.ClockSpeedMin)
+			if qErr != nil {
+				return spec, &intentSpecError{
+					code:    http.StatusBadRequest,
+					err:     "cannot parse quantity for clock speed",
+					context: *in.Workload.Resources.Gpu.ClockSpeedMin,
+				}
+			}
+
+			spec.Workload.Resources.GPU.ClockSpeedMin = qty
+		}
+
+		switch {
+		case in.Workload.Resources.Gpu.ComputeCapability == nil, *in.Workload.Resources.Gpu.ComputeCapability == "any":
+			spec.Workload.Resources.GPU.ComputeCapability = "Any"
+		default:
+			spec.Workload.Resources.GPU.ComputeCapability = string(*in.Workload.Resources.Gpu.ComputeCapability)
+		}
+
+		spec.Workload.Resources.GPU.CoresMax = int64(ptr.Deref(in.Workload.Resources.Gpu.CoresMax, 0))
+		spec.Workload.Resources.GPU.CoresMin = int64(ptr.Deref(in.Workload.Resources.Gpu.CoresMin, 0))
+		spec.Workload.Resources.GPU.Count = int64(ptr.Deref(in.Workload.Resources.Gpu.Count, 0))
+		spec.Workload.Resources.GPU.Dedicated = in.Workload.Resources.Gpu.Dedicated
+		spec.Workload.Resources.GPU.FP32TFlops = float64(ptr.Deref(in.Workload.Resources.Gpu.Fp32Tflops, float32(0)))
+		spec.Workload.Resources.GPU.Interconnect = ptr.Deref(in.Workload.Resources.Gpu.Interconnect, "")
+		spec.Workload.Resources.GPU.Interruptible = in.Workload.Resources.Gpu.Interruptible
+		if in.Workload.Resources.Gpu.Interruptible != nil {
+		}
+
+		if in.Workload.Resources.Gpu.MemoryMax != nil {
+			qty, qErr := resource.ParseQuantity(*in.Workload.Resources.Gpu.MemoryMax)
+			if qErr != nil {
+				return spec, &intentSpecError{
+					code:    http.StatusBadRequest,
+					err:     "cannot parse quantity for max memory",
+					context: *in.Workload.Resources.Gpu.MemoryMax,
+				}
+			}
+
+			spec.Workload.Resources.GPU.MemoryMax = qty
+		}
+
+		if in.Workload.Resources.Gpu.MemoryMin != nil {
+			qty, qErr := resource.ParseQuantity(*in.Workload.Resources.Gpu.MemoryMin)
+			if qErr != nil {
+				return spec, &intentSpecError{
+					code:    http.StatusBadRequest,
+					err:     "cannot parse quantity for min memory",
+					context: *in.Workload.Resources.Gpu.MemoryMin,
+				}
+			}
+
+			spec.Workload.Resources.GPU.MemoryMin = qty
+		}
+
+		switch {
+		case in.Workload.Resources.Gpu.Model == nil, *in.Workload.Resources.Gpu.Model == "any":
+			spec.Workload.Resources.GPU.Model = "Any"
+		default:
+			spec.Workload.Resources.GPU.Model = string(*in.Workload.Resources.Gpu.Model)
+		}
+
+		spec.Workload.Resources.GPU.MultiGPUEfficiency = float64(ptr.Deref(in.Workload.Resources.Gpu.MultiGpuEfficiency, float32(0)))
+		spec.Workload.Resources.GPU.MultiInstance = in.Workload.Resources.Gpu.MultiInstance
+		spec.Workload.Resources.GPU.Shared = in.Workload.Resources.Gpu.Shared
+
+		switch {
+		case in.Workload.Resources.Gpu.Tier == nil, *in.Workload.Resources.Gpu.Tier == "any":
+			spec.Workload.Resources.GPU.Tier = "Any"
+		default:
+			spec.Workload.Resources.GPU.Tier = string(*in.Workload.Resources.Gpu.Tier)
+		}
+
+		if in.Workload.Resources.Gpu.Topology != nil {
+			switch *in.Workload.Resources.Gpu.Topology {
+			case api.AllToAll:
+				spec.Workload.Resources.GPU.Topology = ptr.To("AllToAll")
+			case api.Mesh:
+				spec.Workload.Resources.GPU.Topology = ptr.To("Mesh")
+			case api.Nvswitch:
+				spec.Workload.Resources.GPU.Topology = ptr.To("Nvswitch")
+			case api.Ring:
+				spec.Workload.Resources.GPU.Topology = ptr.To("Ring")
+			default:
+				return spec, &intentSpecError{
+					code:    http.StatusBadRequest,
+					err:     "unhandled GPU topology enum",
+					context: string(*in.Workload.Resources.Gpu.Topology),
+				}
+			}
+		}
+	}
+
+	if in.Workload.Resources.Memory != nil {
+		qty, qErr := resource.ParseQuantity(*in.Workload.Resources.Memory)
+		if qErr != nil {
+			return spec, &intentSpecError{
+				code:    http.StatusBadRequest,
+				err:     "cannot parse quantity for memory",
+				context: *in.Workload.Resources.Memory,
+			}
+		}
+
+		spec.Workload.Resources.Memory = qty
+	}
+
+	if in.Workload.Secrets != nil {
+		spec.Workload.Secrets = make([]flarev1alpha1.IntentWorkloadSecret, 0, len(*in.Workload.Secrets))
+		for _, secret := range *in.Workload.Secrets {
+			if errs := validation.IsConfigMapKey(secret.Name); len(errs) > 0 {
+				return spec, &intentSpecError{
+					code:    http.StatusBadRequest,
+					err:     "invalid secret name, " + strings.Join(errs, ", "),
+					context: secret.Name,
+				}
+			}
+
+			if secret.Value != nil && secret.From != nil {
+				return spec, &intentSpecError{
+					code:    http.StatusBadRequest,
+					err:     "secret value cannot be both inline and referenced from a Tenant secret",
+					context: secret.Name,
+				}
+			}
+
+			spec.Workload.Secrets = append(spec.Workload.Secrets, flarev1alpha1.IntentWorkloadSecret{
+				Name: secret.Name,
+				Env:  secret.Env,
+				From: ptr.Deref(secret.From, ""),
+				Key:  ptr.Deref(secret.Key, ""),
+			})
+		}
+	}
+
+	if in.Workload.Storage != nil && *in.Workload.Storage.Volumes != nil {
+		spec.Workload.Storage.Volumes = make([]flarev1alpha1.IntentWorkloadStorageVolume, 0, len(*in.Workload.Storage.Volumes))
+		for _, volume := range *in.Workload.Storage.Volumes {
+			vol := flarev1alpha1.IntentWorkloadStorageVolume{
+				Name: volume.Name,
+				Path: volume.Path,
+			}
+
+			if qty, qErr := resource.ParseQuantity(volume.Size); qErr != nil {
+				return spec, &intentSpecError{
+					code:    http.StatusBadRequest,
+					err:     "cannot parse quantity for volume size",
+					context: volume.Size,
+				}
+			} else {
+				vol.Size = qty
+			}
+
+			switch volume.Type {
+			case api.Persistent:
+				vol.Type = "Persistent"
+			case api.Temporary:
+				vol.Type = "Temporary"
+			default:
+				return spec, &intentSpecError{
+					code:    http.StatusBadRequest,
+					err:     "unhandled volume type enum",
+					context: string(volume.Type),
+				}
+			}
+
+			if volume.Source != nil {
+				// The credentials are referenced from a Tenant secret, never supplied in the clear.
+				if credentials := ptr.Deref(volume.Source.Credentials, ""); credentials != "" {
+					if errs := validation.IsDNS1123Label(credentials); len(errs) > 0 {
+						return spec, &intentSpecError{
+							code:    http.StatusBadRequest,
+							err:     "invalid source credentials, must be the name of a Tenant secret: " + strings.Join(errs, ", "),
+							context: volume.Name,
+						}
+					}
+				}
+
+				vol.Source = flarev1alpha1.IntentWorkloadStorageVolumeSource{
+					Credentials: ptr.Deref(volume.Source.Credentials, ""),
+					Type:        string(ptr.Deref(volume.Source.Type, "")),
+					Uri:         ptr.Deref(volume.Source.Uri, ""),
+				}
+			}
+
+			spec.Workload.Storage.Volumes = append(spec.Workload.Storage.Volumes, vol)
+		}
+	}
+
+	return spec, nil
+}
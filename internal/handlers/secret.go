@@ -0,0 +1,372 @@
+// Copyright 2025 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package handlers
+
+import (
+	"context"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	flarev1alpha1 "github.com/clastix/flare-internal/api/v1alpha1"
+	"github.com/clastix/flare-internal/internal/api"
+)
+
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;delete
+
+// Secret manages the Tenant secrets the workload secrets can be copied from,
+// stored along with the Tenant tokens: their values are never returned.
+type Secret struct {
+	Helper Helper
+	Client client.Client
+}
+
+func (s *Secret) ListSecrets(ctx echo.Context) error {
+	user := ctx.Get("user").(authenticationv1.UserInfo)
+
+	tnt, notFoundErr := s.Helper.RetrieveCapsuleTenant(ctx.Request().Context(), user)
+	if notFoundErr != nil {
+		if apierrors.IsNotFound(notFoundErr) {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{
+				"error": "user is not assigned to any Tenant",
+			})
+		}
+
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{
+			"error":   notFoundErr.Error(),
+			"context": "cannot retrieve list of Tenants",
+		})
+	}
+
+	var secretList corev1.SecretList
+	if err := s.Client.List(ctx.Request().Context(), &secretList, client.InNamespace("tenants"), client.MatchingLabels{flarev1alpha1.TenantSecretLabel: tnt.Name}); err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{
+			"error":   err.Error(),
+			"context": "cannot list Secret",
+		})
+	}
+
+	secrets := make([]api.TenantSecret, 0, len(secretList.Items))
+	for _, secret := range secretList.Items {
+		secrets = append(secrets, tenantSecretToAPI(tnt.Name, secret))
+	}
+
+	return ctx.JSON(200, api.ListSecretsResponse{
+		Secrets: &secrets,
+	})
+}
+
+// CreateSecret stores the Tenant secret, replacing the values of an existing one with the same name:
+// the Intents referencing it get the new values at their next reconciliation.
+func (s *Secret) CreateSecret(ctx echo.Context) error {
+	user := ctx.Get("user").(authenticationv1.UserInfo)
+
+	var body api.CreateSecretRequest
+	if err := ctx.Bind(&body); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	if errs := validation.IsDNS1123Label(body.Name); len(errs) > 0 {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{
+			"error":   "invalid secret name, " + strings.Join(errs, ", "),
+			"context": body.Name,
+		})
+	}
+
+	if len(body.Data) == 0 {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{
+			"error": "missing data key in body",
+		})
+	}
+
+	data := make(map[string][]byte, len(body.Data))
+
+	for key, value := range body.Data {
+		if errs := validation.IsConfigMapKey(key); len(errs) > 0 {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{
+				"error":   "invalid secret key, " + strings.Join(errs, ", "),
+				"context": key,
+			})
+		}
+
+		data[key] = []byte(value)
+	}
+
+	tnt, notFoundErr := s.Helper.RetrieveCapsuleTenant(ctx.Request().Context(), user)
+	if notFoundErr != nil {
+		if apierrors.IsNotFound(notFoundErr) {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{
+				"error": "user is not assigned to any Tenant",
+			})
+		}
+
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{
+			"error":   notFoundErr.Error(),
+			"context": "cannot retrieve list of Tenants",
+		})
+	}
+
+	var secret corev1.Secret
+	secret.Name = flarev1alpha1.TenantSecretName(tnt.Name, body.Name)
+	secret.Namespace = "tenants"
+
+	if _, err := controllerutil.CreateOrUpdate(ctx.Request().Context(), s.Client, &secret, func() error {
+		if secret.Labels == nil {
+			secret.Labels = map[string]string{}
+		}
+
+		secret.Labels[flarev1alpha1.TenantSecretLabel] = tnt.Name
+		secret.Type = corev1.SecretTypeOpaque
+		secret.Data = data
+
+		return nil
+	}); err != nil {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{
+			"error":   err.Error(),
+			"context": "cannot store Secret",
+		})
+	}
+
+	return ctx.JSON(200, tenantSecretToAPI(tnt.Name, secret))
+}
+
+// DeleteSecret deletes the Tenant secret, unless any Intent of the Tenant is referencing it.
+func (s *Secret) DeleteSecret(ctx echo.Context, secretName string) error {
+	user := ctx.Get("user").(authenticationv1.UserInfo)
+
+	tnt, notFoundErr := s.Helper.RetrieveCapsuleTenant(ctx.Request().Context(), user)
+	if notFoundErr != nil {
+		if apierrors.IsNotFound(notFoundErr) {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{
+				"error": "user is not assigned to any Tenant",
+			})
+		}
+
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{
+			"error":   notFoundErr.Error(),
+			"context": "cannot retrieve list of Tenants",
+		})
+	}
+
+	var secret corev1.Secret
+	if err := s.Client.Get(ctx.Request().Context(), types.NamespacedName{Namespace: "tenants", Name: flarev1alpha1.TenantSecretName(tnt.Name, secretName)}, &secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctx.JSON(http.StatusNotFound, map[string]string{
+				"error": "secret not found",
+			})
+		}
+
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{
+			"error":   err.Error(),
+			"context": "cannot retrieve Secret",
+		})
+	}
+
+	if secret.Labels[flarev1alpha1.TenantSecretLabel] != tnt.Name {
+		return ctx.JSON(http.StatusNotFound, map[string]string{
+			"error": "secret not found",
+		})
+	}
+
+	for _, ns := range tnt.Status.Namespaces {
+		var intentList flarev1alpha1.IntentList
+		if err := s.Client.List(ctx.Request().Context(), &intentList, client.InNamespace(ns)); err != nil {
+			return ctx.JSON(http.StatusInternalServerError, map[string]string{
+				"error":   err.Error(),
+				"context": "cannot retrieve list of Intents",
+			})
+		}
+
+		for _, intent := range intentList.Items {
+			if slices.ContainsFunc(intent.Spec.Workload.Secrets, func(ref flarev1alpha1.IntentWorkloadSecret) bool {
+				return ref.From == secretName
+			}) {
+				return ctx.JSON(http.StatusConflict, map[string]string{
+					"error":   "secret is referenced by an intent",
+					"context": string(intent.UID),
+				})
+			}
+		}
+	}
+
+	if err := s.Client.Delete(ctx.Request().Context(), &secret); err != nil && !apierrors.IsNotFound(err) {
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{
+			"error":   err.Error(),
+			"context": "cannot delete Secret",
+		})
+	}
+
+	return ctx.JSON(200, api.DeleteSecretResponse{
+		DeletedAt: ptr.To(time.Now()),
+		Message:   nil,
+		Name:      &secretName,
+	})
+}
+
+// tenantSecretToAPI returns the Tenant secret keys, hiding the values.
+func tenantSecretToAPI(tenant string, secret corev1.Secret) api.TenantSecret {
+	return api.TenantSecret{
+		CreatedAt: ptr.To(secret.CreationTimestamp.Time),
+		Keys:      ptr.To(slices.Sorted(maps.Keys(secret.Data))),
+		Name:      ptr.To(strings.TrimPrefix(secret.Name, flarev1alpha1.TenantSecretName(tenant, ""))),
+	}
+}
+
+// inlineSecrets extracts the secret values supplied inline with the submitted Intent,
+// removing them from the submission since it's stored in the Intent annotations:
+// the submission must be validated beforehand, being unable to tell the inline values apart afterwards.
+func inlineSecrets(in *api.Intent) map[string][]byte {
+	if in.Workload.Secrets == nil {
+		return nil
+	}
+
+	values := map[string][]byte{}
+
+	for index, secret := range *in.Workload.Secrets {
+		if secret.Value == nil {
+			continue
+		}
+
+		values[secret.Name] = []byte(*secret.Value)
+		(*in.Workload.Secrets)[index].Value = nil
+	}
+
+	return values
+}
+
+// validateSecretReferences ensures the Tenant secrets referenced by the workload exist, along with their keys,
+// as well as the ones storing the volume source credentials.
+func validateSecretReferences(ctx context.Context, clt client.Client, tenant string, spec flarev1alpha1.IntentSpec) *intentSpecError {
+	for _, volume := range spec.Workload.Storage.Volumes {
+		if volume.Source.Credentials == "" {
+			continue
+		}
+
+		var secret corev1.Secret
+		if err := clt.Get(ctx, types.NamespacedName{Namespace: "tenants", Name: flarev1alpha1.TenantSecretName(tenant, volume.Source.Credentials)}, &secret); err != nil {
+			if apierrors.IsNotFound(err) {
+				return &intentSpecError{
+					code:    http.StatusBadRequest,
+					err:     "referenced Tenant secret not found",
+					context: volume.Source.Credentials,
+				}
+			}
+
+			return &intentSpecError{
+				code:    http.StatusInternalServerError,
+				err:     err.Error(),
+				context: "cannot retrieve Secret",
+			}
+		}
+	}
+
+	for _, ref := range spec.Workload.Secrets {
+		if ref.From == "" {
+			continue
+		}
+
+		var secret corev1.Secret
+		if err := clt.Get(ctx, types.NamespacedName{Namespace: "tenants", Name: flarev1alpha1.TenantSecretName(tenant, ref.From)}, &secret); err != nil {
+			if apierrors.IsNotFound(err) {
+				return &intentSpecError{
+					code:    http.StatusBadRequest,
+					err:     "referenced Tenant secret not found",
+					context: ref.From,
+				}
+			}
+
+			return &intentSpecError{
+				code:    http.StatusInternalServerError,
+				err:     err.Error(),
+				context: "cannot retrieve Secret",
+			}
+		}
+
+		key := ref.Key
+		if key == "" {
+			key = ref.Name
+		}
+
+		if _, ok := secret.Data[key]; !ok {
+			return &intentSpecError{
+				code:    http.StatusBadRequest,
+				err:     "referenced Tenant secret has no such key",
+				context: ref.From + "/" + key,
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateSecretValues ensures each secret of the workload has a value: supplied inline with the submission,
+// referenced from a Tenant secret, or stored upon a previous submission of the Intent, if any.
+func validateSecretValues(ctx context.Context, clt client.Client, intent *flarev1alpha1.Intent, spec flarev1alpha1.IntentSpec, inline map[string][]byte) *intentSpecError {
+	var stored corev1.Secret
+
+	if intent != nil {
+		if err := clt.Get(ctx, types.NamespacedName{Namespace: intent.Namespace, Name: flarev1alpha1.InlineSecretName(intent)}, &stored); err != nil && !apierrors.IsNotFound(err) {
+			return &intentSpecError{
+				code:    http.StatusInternalServerError,
+				err:     err.Error(),
+				context: "cannot retrieve inline secrets",
+			}
+		}
+	}
+
+	for _, secret := range spec.Workload.Secrets {
+		if _, ok := inline[secret.Name]; ok || secret.From != "" {
+			continue
+		}
+
+		if _, ok := stored.Data[secret.Name]; !ok {
+			return &intentSpecError{
+				code:    http.StatusBadRequest,
+				err:     "secret value must be supplied inline, or referenced from a Tenant secret",
+				context: secret.Name,
+			}
+		}
+	}
+
+	return nil
+}
+
+// storeInlineSecrets stores the secret values supplied inline in the Intent Namespace,
+// merging them with the ones of the previous submissions: the operator copies them in the workload Secret.
+func storeInlineSecrets(ctx context.Context, clt client.Client, intent *flarev1alpha1.Intent, values map[string][]byte) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	var secret corev1.Secret
+	secret.Name = flarev1alpha1.InlineSecretName(intent)
+	secret.Namespace = intent.Namespace
+
+	_, err := controllerutil.CreateOrUpdate(ctx, clt, &secret, func() error {
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+
+		secret.Type = corev1.SecretTypeOpaque
+		maps.Copy(secret.Data, values)
+
+		return nil
+	})
+
+	return err
+}
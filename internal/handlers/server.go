@@ -5,5 +5,6 @@ package handlers
 
 type Server struct {
 	Intent
+	Secret
 	Token
 }
@@ -6,26 +6,60 @@ package handlers
 import (
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
 	authenticationv1 "k8s.io/api/authentication/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/client-go/util/retry"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	flarev1alpha1 "github.com/clastix/flare-internal/api/v1alpha1"
 	"github.com/clastix/flare-internal/internal/api"
+	"github.com/clastix/flare-internal/internal/middlewares"
+	"github.com/clastix/flare-internal/internal/permission"
 )
 
-//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;delete
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;delete
+//+kubebuilder:rbac:groups="",resources=serviceaccounts/token,verbs=create
+
+const (
+	// tokenDefaultTTL is the lifetime of the tokens created with no requested one.
+	tokenDefaultTTL = 90 * 24 * time.Hour
+	// tokenMinTTL is the shortest lifetime the TokenRequest API accepts.
+	tokenMinTTL = 10 * time.Minute
+)
+
+// parseTokenTTL parses the requested token lifetime, as a Go duration or an amount of days and years (e.g. "30d", "1y").
+func parseTokenTTL(value string) (time.Duration, error) {
+	units := map[string]time.Duration{
+		"d": 24 * time.Hour,
+		"y": 365 * 24 * time.Hour,
+	}
+
+	for suffix, unit := range units {
+		if amount, found := strings.CutSuffix(value, suffix); found {
+			n, err := strconv.Atoi(amount)
+			if err != nil {
+				return 0, err
+			}
+
+			return time.Duration(n) * unit, nil
+		}
+	}
+
+	return time.ParseDuration(value)
+}
 
 type Token struct {
 	Helper Helper
 	Client client.Client
+	// TokenReviewCache is evicted of the revoked tokens, if any.
+	TokenReviewCache *middlewares.TokenReviewCache
 }
 
 func (t *Token) ListTokens(ctx echo.Context) error {
@@ -55,11 +89,26 @@ func (t *Token) ListTokens(ctx echo.Context) error {
 
 	var tokenList []api.Token
 	for _, secret := range secretList.Items {
-		tokenList = append(tokenList, api.Token{
+		token := api.Token{
 			CreatedAt: ptr.To(secret.CreationTimestamp.Time),
 			Name:      ptr.To(strings.ReplaceAll(secret.Name, secret.GenerateName, "")),
+			Status:    ptr.To("active"),
 			TokenId:   ptr.To(string(secret.UID)),
-		})
+		}
+
+		if value, ok := secret.Annotations[flarev1alpha1.TokenPermissionsAnnotation]; ok {
+			token.Permissions = ptr.To(sets.List(permission.Parse(value)))
+		}
+
+		if expiresAt, err := time.Parse(time.RFC3339, secret.Annotations[flarev1alpha1.TokenExpirationAnnotation]); err == nil {
+			token.ExpiresAt = ptr.To(expiresAt)
+
+			if time.Now().After(expiresAt) {
+				token.Status = ptr.To("expired")
+			}
+		}
+
+		tokenList = append(tokenList, token)
 	}
 
 	return ctx.JSON(200, api.ListTokensResponse{
@@ -67,9 +116,51 @@ func (t *Token) ListTokens(ctx echo.Context) error {
 	})
 }
 
+// CreateToken issues a token of the Tenant ServiceAccount through the TokenRequest API, bound to a Secret
+// storing its permissions and expiration: the token is revoked along with the Secret.
+// A restricted token can only issue tokens restricted to the permissions it holds.
 func (t *Token) CreateToken(ctx echo.Context) error {
 	user := ctx.Get("user").(authenticationv1.UserInfo)
 
+	var body api.CreateTokenRequest
+	if err := ctx.Bind(&body); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	requested := sets.New[string](permission.Default()...)
+	if body.Permissions != nil {
+		requested = sets.New[string](*body.Permissions...)
+	}
+
+	for _, value := range sets.List(requested) {
+		if !permission.All.Has(value) {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{
+				"error":   "unknown token permission",
+				"context": value,
+			})
+		}
+
+		if !permission.Grants(ctx.Get("permissions").(sets.Set[string]), value) {
+			return ctx.JSON(http.StatusForbidden, map[string]string{
+				"error":   "token cannot grant permissions it does not hold",
+				"context": value,
+			})
+		}
+	}
+
+	ttl := tokenDefaultTTL
+	if body.ExpiresIn != nil {
+		var err error
+		if ttl, err = parseTokenTTL(*body.ExpiresIn); err != nil || ttl < tokenMinTTL {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{
+				"error":   fmt.Sprintf("expires_in must be a duration of at least %s (e.g. \"12h\", \"30d\", \"1y\")", tokenMinTTL),
+				"context": *body.ExpiresIn,
+			})
+		}
+	}
+
 	tnt, notFoundErr := t.Helper.RetrieveCapsuleTenant(ctx.Request().Context(), user)
 	if notFoundErr != nil {
 		if apierrors.IsNotFound(notFoundErr) {
@@ -91,59 +182,63 @@ func (t *Token) CreateToken(ctx echo.Context) error {
 		"tenant": tnt.Name,
 	}
 	secret.Annotations = map[string]string{
-		corev1.ServiceAccountNameKey: tnt.Name,
+		corev1.ServiceAccountNameKey:             tnt.Name,
+		flarev1alpha1.TokenPermissionsAnnotation: permission.Format(requested),
 	}
-	secret.Type = corev1.SecretTypeServiceAccountToken
+	secret.Type = corev1.SecretTypeOpaque
 
 	if err := t.Client.Create(ctx.Request().Context(), &secret); err != nil {
 		return ctx.JSON(http.StatusInternalServerError, map[string]string{
 			"error":   err.Error(),
-			"context": "cannot create ServiceAccount",
+			"context": "cannot create Secret",
 		})
 	}
 
-	err := retry.OnError(retry.DefaultRetry, func(err error) bool {
-		return err != nil
-	}, func() error {
-		if err := t.Client.Get(ctx.Request().Context(), client.ObjectKeyFromObject(&secret), &secret); err != nil {
-			return err
-		}
+	var serviceAccount corev1.ServiceAccount
+	serviceAccount.Name = tnt.Name
+	serviceAccount.Namespace = "tenants"
 
-		if secret.Data == nil {
-			return fmt.Errorf("missing data")
-		}
+	var tokenRequest authenticationv1.TokenRequest
+	tokenRequest.Spec.ExpirationSeconds = ptr.To(int64(ttl.Seconds()))
+	tokenRequest.Spec.BoundObjectRef = &authenticationv1.BoundObjectReference{
+		Kind:       "Secret",
+		APIVersion: "v1",
+		Name:       secret.Name,
+		UID:        secret.UID,
+	}
 
-		if secret.Data[corev1.ServiceAccountTokenKey] == nil {
-			return fmt.Errorf("missing token")
-		}
+	if err := t.Client.SubResource("token").Create(ctx.Request().Context(), &serviceAccount, &tokenRequest); err != nil {
+		_ = t.Client.Delete(ctx.Request().Context(), &secret)
 
-		return nil
-	})
-	if err != nil {
 		return ctx.JSON(http.StatusInternalServerError, map[string]string{
 			"error":   err.Error(),
 			"context": "cannot create Token",
 		})
 	}
+	// The API Server could shorten the requested lifetime, the expiration is tracked once the token is issued.
+	expiresAt := tokenRequest.Status.ExpirationTimestamp.Time
+	secret.Annotations[flarev1alpha1.TokenExpirationAnnotation] = expiresAt.UTC().Format(time.RFC3339)
+
+	if err := t.Client.Update(ctx.Request().Context(), &secret); err != nil {
+		_ = t.Client.Delete(ctx.Request().Context(), &secret)
 
-	token, _, tokenErr := jwt.NewParser().ParseUnverified(string(secret.Data[corev1.ServiceAccountTokenKey]), jwt.MapClaims{})
-	if tokenErr != nil {
 		return ctx.JSON(http.StatusInternalServerError, map[string]string{
-			"error":   tokenErr.Error(),
-			"context": "cannot parse JWT",
+			"error":   err.Error(),
+			"context": "cannot update Secret",
 		})
 	}
 
 	return ctx.JSON(200, api.CreateTokenResponse{
 		CreatedAt:   ptr.To(secret.CreationTimestamp.Time),
-		ExpiresAt:   nil,
+		ExpiresAt:   ptr.To(expiresAt),
 		Name:        ptr.To(strings.ReplaceAll(secret.Name, secret.GenerateName, "")),
-		Permissions: nil,
-		Token:       ptr.To(token.Raw),
+		Permissions: ptr.To(sets.List(requested)),
+		Token:       ptr.To(tokenRequest.Status.Token),
 		TokenId:     ptr.To(string(secret.UID)),
 	})
 }
 
+// RevokeToken deletes the Secret the token is bound to, invalidating it.
 func (t *Token) RevokeToken(ctx echo.Context, tokenId string) error {
 	user := ctx.Get("user").(authenticationv1.UserInfo)
 
@@ -178,6 +273,10 @@ func (t *Token) RevokeToken(ctx echo.Context, tokenId string) error {
 				})
 			}
 
+			if t.TokenReviewCache != nil {
+				t.TokenReviewCache.Revoke(secret.Name)
+			}
+
 			return ctx.JSON(200, api.RevokeTokenResponse{
 				Message:   nil,
 				RevokedAt: ptr.To(time.Now()),
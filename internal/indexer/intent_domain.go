@@ -0,0 +1,40 @@
+// Copyright 2025 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package indexer
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	flarev1alpha1 "github.com/clastix/flare-internal/api/v1alpha1"
+)
+
+// IntentDomain indexes the Intents upon the custom domains of their exposed ports.
+type IntentDomain struct{}
+
+func (i IntentDomain) Object() client.Object {
+	return &flarev1alpha1.Intent{}
+}
+
+func (i IntentDomain) Field() string {
+	return "spec.workload.ports.domain"
+}
+
+func (i IntentDomain) Func() client.IndexerFunc {
+	return func(object client.Object) []string {
+		intent, ok := object.(*flarev1alpha1.Intent)
+		if !ok {
+			return nil
+		}
+
+		var domains []string
+
+		for _, port := range intent.Spec.Workload.Ports {
+			if port.Expose && port.Domain != "" {
+				domains = append(domains, port.Domain)
+			}
+		}
+
+		return domains
+	}
+}
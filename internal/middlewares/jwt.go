@@ -4,6 +4,8 @@
 package middlewares
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 	"strings"
 
@@ -12,9 +14,57 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// Authenticator authenticates the bearer tokens of the API requests, returning the user the token has been issued to:
+// a nil user is returned for the tokens the Authenticator is not meant for, letting the next one authenticate them.
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (*v1.UserInfo, error)
+}
+
 //+kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=get;list;watch;create
 
-func JWTAuthenticationMiddleware(client client.Client) echo.MiddlewareFunc {
+// ServiceAccountAuthenticator authenticates the ServiceAccount tokens through the TokenReview API,
+// rejecting the ones of any other user.
+type ServiceAccountAuthenticator struct {
+	Client client.Client
+	// Cache stores the reviewed tokens, sparing a TokenReview for each request: nil disables caching.
+	Cache *TokenReviewCache
+}
+
+func (s *ServiceAccountAuthenticator) Authenticate(ctx context.Context, token string) (*v1.UserInfo, error) {
+	if s.Cache != nil {
+		if user, ok := s.Cache.Get(token); ok {
+			return user, nil
+		}
+	}
+
+	var tokenReview v1.TokenReview
+	tokenReview.Spec.Token = token
+	if err := s.Client.Create(ctx, &tokenReview); err != nil {
+		return nil, err
+	}
+
+	if !tokenReview.Status.Authenticated {
+		return nil, fmt.Errorf("unauthenticated user")
+	}
+
+	if tokenReview.Status.Error != "" {
+		return nil, fmt.Errorf("TokenReview returned the following error: %s", tokenReview.Status.Error)
+	}
+
+	if !strings.HasPrefix(tokenReview.Status.User.Username, "system:serviceaccount:") {
+		return nil, fmt.Errorf("only ServiceAccount can access APIs")
+	}
+
+	// Caching only the ServiceAccount users, the other ones are reviewed again at each request.
+	if s.Cache != nil {
+		s.Cache.Add(token, &tokenReview.Status.User)
+	}
+
+	return &tokenReview.Status.User, nil
+}
+
+// JWTAuthenticationMiddleware authenticates the bearer token of the request with the first Authenticator meant for it.
+func JWTAuthenticationMiddleware(authenticators ...Authenticator) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			auth := c.Request().Header.Get("Authorization")
@@ -22,29 +72,25 @@ func JWTAuthenticationMiddleware(client client.Client) echo.MiddlewareFunc {
 				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "missing or invalid token"})
 			}
 
-			var tokenReview v1.TokenReview
-			tokenReview.Spec.Token = strings.TrimPrefix(auth, "Bearer ")
-			if err := client.Create(c.Request().Context(), &tokenReview); err != nil {
-				return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
-			}
+			token := strings.TrimPrefix(auth, "Bearer ")
 
-			if !tokenReview.Status.Authenticated {
-				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthenticated user"})
-			}
+			for _, authenticator := range authenticators {
+				user, err := authenticator.Authenticate(c.Request().Context(), token)
+				if err != nil {
+					return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
+				}
 
-			if tokenReview.Status.Error != "" {
-				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "TokenReview returned the following error: " + tokenReview.Status.Error})
-			}
+				if user == nil {
+					continue
+				}
 
-			if parts := strings.Split(tokenReview.Status.User.Username, ":"); parts[0] != "system" && parts[1] != "serviceaccount" {
-				return c.JSON(http.StatusUnauthorized, map[string]string{
-					"error": "only ServiceAccount can access APIs",
-				})
-			}
+				c.Set("user", *user)
+				c.Set("token", token)
 
-			c.Set("user", tokenReview.Status.User)
+				return next(c)
+			}
 
-			return next(c)
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthenticated user"})
 		}
 	}
 }
@@ -0,0 +1,292 @@
+// Copyright 2025 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package middlewares
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/authentication/v1"
+
+	flarev1alpha1 "github.com/clastix/flare-internal/api/v1alpha1"
+)
+
+// oidcMinRefreshInterval bounds the JWKS refreshes triggered by tokens signed with an unknown key,
+// preventing forged tokens from hammering the OIDC provider.
+const oidcMinRefreshInterval = time.Minute
+
+// OIDCAuthenticator authenticates the ID tokens issued by the OIDC provider, verifying their signature
+// upon the provider JWKS, cached and refreshed at the given interval or when a token is signed with an unknown key.
+// The users are mapped to the Capsule Tenants they own, as User or through their Groups, or to the one named by the tenant claim.
+type OIDCAuthenticator struct {
+	// IssuerURL is the URL of the OIDC provider, which must match the issuer of the tokens.
+	IssuerURL string
+	// ClientID is the audience the tokens must be issued for.
+	ClientID string
+	// UsernameClaim is the claim the username is taken from.
+	UsernameClaim string
+	// UsernamePrefix is prepended to the username, preventing clashes with the other users.
+	UsernamePrefix string
+	// GroupsClaim is the claim the groups are taken from.
+	GroupsClaim string
+	// GroupsPrefix is prepended to the groups, preventing clashes with the other groups.
+	GroupsPrefix string
+	// TenantClaim is the claim naming the Capsule Tenant of the user, taking precedence over the Tenant ownership.
+	TenantClaim string
+	// RefreshInterval is the interval the JWKS is refreshed at.
+	RefreshInterval time.Duration
+
+	mutex       sync.RWMutex
+	keys        map[string]crypto.PublicKey
+	refreshedAt time.Time
+}
+
+func (o *OIDCAuthenticator) Authenticate(ctx context.Context, token string) (*v1.UserInfo, error) {
+	// Tokens issued by anyone else than the provider are left to the next Authenticator.
+	var unverified jwt.RegisteredClaims
+	if _, _, err := jwt.NewParser().ParseUnverified(token, &unverified); err != nil || unverified.Issuer != o.IssuerURL {
+		return nil, nil
+	}
+
+	claims := jwt.MapClaims{}
+
+	_, err := jwt.NewParser(
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}),
+		jwt.WithIssuer(o.IssuerURL),
+		jwt.WithAudience(o.ClientID),
+		jwt.WithExpirationRequired(),
+	).ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+
+		return o.key(ctx, kid)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid OIDC token")
+	}
+
+	username, _ := claims[o.UsernameClaim].(string)
+	if username == "" {
+		return nil, fmt.Errorf("OIDC token has no %s claim", o.UsernameClaim)
+	}
+
+	if verified, ok := claims["email_verified"].(bool); o.UsernameClaim == "email" && ok && !verified {
+		return nil, fmt.Errorf("OIDC token email is not verified")
+	}
+
+	user := &v1.UserInfo{
+		Username: o.UsernamePrefix + username,
+	}
+	// The ServiceAccounts and the other Kubernetes system users cannot be impersonated.
+	if strings.HasPrefix(user.Username, "system:") {
+		return nil, fmt.Errorf("OIDC username cannot start with system:")
+	}
+
+	switch groups := claims[o.GroupsClaim].(type) {
+	case string:
+		user.Groups = []string{o.GroupsPrefix + groups}
+	case []interface{}:
+		for _, group := range groups {
+			if name, ok := group.(string); ok {
+				user.Groups = append(user.Groups, o.GroupsPrefix+name)
+			}
+		}
+	}
+
+	if tenant, _ := claims[o.TenantClaim].(string); o.TenantClaim != "" && tenant != "" {
+		user.Extra = map[string]v1.ExtraValue{
+			flarev1alpha1.TenantUserExtraKey: {tenant},
+		}
+	}
+
+	return user, nil
+}
+
+// key returns the public key of the provider with the given ID, refreshing the JWKS when stale, or when the key is unknown.
+func (o *OIDCAuthenticator) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	o.mutex.RLock()
+	key, ok := o.keys[kid]
+	refreshedAt := o.refreshedAt
+	o.mutex.RUnlock()
+
+	if ok && time.Since(refreshedAt) < o.RefreshInterval {
+		return key, nil
+	}
+
+	if !ok && time.Since(refreshedAt) < oidcMinRefreshInterval {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	// Another request could have refreshed the keys in the meanwhile.
+	if o.refreshedAt.After(refreshedAt) {
+		if key, ok = o.keys[kid]; ok {
+			return key, nil
+		}
+
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	keys, err := o.fetchKeys(ctx)
+	o.refreshedAt = time.Now()
+
+	if err != nil {
+		// Keeping the cached keys upon the provider unavailability.
+		if key, ok = o.keys[kid]; ok {
+			return key, nil
+		}
+
+		return nil, errors.Wrap(err, "cannot retrieve OIDC provider keys")
+	}
+
+	o.keys = keys
+
+	if key, ok = o.keys[kid]; ok {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// jsonWebKey is a public key of the provider JWKS: only the RSA and EC ones are supported.
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// fetchKeys retrieves the provider JWKS, discovered from the OpenID configuration of the issuer.
+func (o *OIDCAuthenticator) fetchKeys(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	var configuration struct {
+		Issuer  string `json:"issuer"`
+		JWKSURI string `json:"jwks_uri"`
+	}
+
+	if err := o.getJSON(ctx, strings.TrimSuffix(o.IssuerURL, "/")+"/.well-known/openid-configuration", &configuration); err != nil {
+		return nil, err
+	}
+
+	if configuration.Issuer != o.IssuerURL {
+		return nil, fmt.Errorf("OIDC provider issuer %q does not match %q", configuration.Issuer, o.IssuerURL)
+	}
+
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+
+	if err := o.getJSON(ctx, configuration.JWKSURI, &jwks); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(jwks.Keys))
+
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		key, err := jwk.publicKey()
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid key "+jwk.Kid)
+		}
+
+		if key != nil {
+			keys[jwk.Kid] = key
+		}
+	}
+
+	return keys, nil
+}
+
+func (o *OIDCAuthenticator) getJSON(ctx context.Context, url string, out interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %d", url, res.StatusCode)
+	}
+
+	return json.NewDecoder(res.Body).Decode(out)
+}
+
+// publicKey returns the public key of the JWK, nil for the unsupported key types.
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	decode := func(value string) (*big.Int, error) {
+		data, err := base64.RawURLEncoding.DecodeString(value)
+		if err != nil {
+			return nil, err
+		}
+
+		return new(big.Int).SetBytes(data), nil
+	}
+
+	switch k.Kty {
+	case "RSA":
+		n, err := decode(k.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decode(k.E)
+		if err != nil {
+			return nil, err
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		curves := map[string]elliptic.Curve{
+			"P-256": elliptic.P256(),
+			"P-384": elliptic.P384(),
+			"P-521": elliptic.P521(),
+		}
+
+		curve, ok := curves[k.Crv]
+		if !ok {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+
+		x, err := decode(k.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decode(k.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, nil
+	}
+}
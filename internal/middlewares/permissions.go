@@ -0,0 +1,87 @@
+// Copyright 2025 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	flarev1alpha1 "github.com/clastix/flare-internal/api/v1alpha1"
+	"github.com/clastix/flare-internal/internal/permission"
+)
+
+// legacySecretNameClaim is the claim of the legacy ServiceAccount tokens referencing the Secret they're stored in.
+const legacySecretNameClaim = "kubernetes.io/serviceaccount/secret.name"
+
+// tokenSecretName returns the name of the Secret the token has been issued for: the Secret the bound tokens
+// are bound to, or the one storing the legacy tokens. An empty name is returned for the tokens issued otherwise.
+func tokenSecretName(token string) string {
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(token, claims); err != nil {
+		return ""
+	}
+
+	if name, ok := claims[legacySecretNameClaim].(string); ok {
+		return name
+	}
+
+	private, _ := claims["kubernetes.io"].(map[string]interface{})
+	secret, _ := private["secret"].(map[string]interface{})
+	name, _ := secret["name"].(string)
+
+	return name
+}
+
+// PermissionsMiddleware enforces the permission each route requires, upon the ones granted to the token
+// authenticated by JWTAuthenticationMiddleware: tokens which have not been issued by the API are not restricted.
+// The granted permissions are stored in the context, nil for the tokens which are not restricted.
+func PermissionsMiddleware(client client.Client) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			var permissions sets.Set[string]
+
+			if name := tokenSecretName(c.Get("token").(string)); name != "" {
+				var secret corev1.Secret
+				if err := client.Get(c.Request().Context(), types.NamespacedName{Namespace: "tenants", Name: name}, &secret); err != nil {
+					// The token could have been revoked by another replica, while still cached by this one.
+					if apierrors.IsNotFound(err) {
+						return c.JSON(http.StatusUnauthorized, map[string]string{"error": "token has been revoked"})
+					}
+
+					return c.JSON(http.StatusInternalServerError, map[string]string{
+						"error":   err.Error(),
+						"context": "cannot retrieve token permissions",
+					})
+				}
+
+				if value, ok := secret.Annotations[flarev1alpha1.TokenPermissionsAnnotation]; ok {
+					permissions = permission.Parse(value)
+				}
+			}
+
+			// Failing closed, the routes with no mapped permission are reserved to the administrators.
+			required, ok := permission.Routes[c.Request().Method+" "+c.Path()]
+			if !ok {
+				required = permission.Admin
+			}
+
+			if !permission.Grants(permissions, required) {
+				return c.JSON(http.StatusForbidden, map[string]string{
+					"error": "token is not granted the " + required + " permission",
+				})
+			}
+
+			c.Set("permissions", permissions)
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2025 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package middlewares
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/util/cache"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var tokenReviewCacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "flare_tokenreview_cache_requests_total",
+	Help: "Number of the ServiceAccount tokens authenticated through the TokenReview cache, by result (hit or miss).",
+}, []string{"result"})
+
+func init() {
+	metrics.Registry.MustRegister(tokenReviewCacheRequests)
+}
+
+// tokenReviewCacheEntry is the user a token has been reviewed for, along with the Secret the token is bound to.
+type tokenReviewCacheEntry struct {
+	user       v1.UserInfo
+	secretName string
+}
+
+// TokenReviewCache caches the users of the reviewed ServiceAccount tokens, keyed by the token hash: the least recently
+// used entries are evicted once the cache is full, and each entry expires after the TTL, or along with its token.
+type TokenReviewCache struct {
+	lru *cache.LRUExpireCache
+	ttl time.Duration
+}
+
+func NewTokenReviewCache(size int, ttl time.Duration) *TokenReviewCache {
+	return &TokenReviewCache{
+		lru: cache.NewLRUExpireCache(size),
+		ttl: ttl,
+	}
+}
+
+func tokenReviewCacheKey(token string) string {
+	hash := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(hash[:])
+}
+
+// Get returns the user the token has been reviewed for, if cached.
+func (t *TokenReviewCache) Get(token string) (*v1.UserInfo, bool) {
+	value, ok := t.lru.Get(tokenReviewCacheKey(token))
+	if !ok {
+		tokenReviewCacheRequests.WithLabelValues("miss").Inc()
+
+		return nil, false
+	}
+
+	tokenReviewCacheRequests.WithLabelValues("hit").Inc()
+
+	user := value.(tokenReviewCacheEntry).user
+
+	return user.DeepCopy(), true
+}
+
+// Add caches the user the token has been reviewed for, until the TTL or the token expiration, whichever comes first.
+func (t *TokenReviewCache) Add(token string, user *v1.UserInfo) {
+	ttl := t.ttl
+
+	var claims jwt.RegisteredClaims
+	if _, _, err := jwt.NewParser().ParseUnverified(token, &claims); err == nil && claims.ExpiresAt != nil {
+		ttl = min(ttl, time.Until(claims.ExpiresAt.Time))
+	}
+
+	if ttl <= 0 {
+		return
+	}
+
+	t.lru.Add(tokenReviewCacheKey(token), tokenReviewCacheEntry{user: *user.DeepCopy(), secretName: tokenSecretName(token)}, ttl)
+}
+
+// Revoke evicts the tokens bound to the given Secret, which must be reviewed again once revoked.
+func (t *TokenReviewCache) Revoke(secretName string) {
+	for _, key := range t.lru.Keys() {
+		if value, ok := t.lru.Get(key); ok && value.(tokenReviewCacheEntry).secretName == secretName {
+			t.lru.Remove(key)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2025 Clastix Labs
+// SPDX-License-Identifier: Apache-2.0
+
+package permission
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// Permissions the API tokens can be granted.
+const (
+	IntentsRead   = "intents:read"
+	IntentsWrite  = "intents:write"
+	IntentsDelete = "intents:delete"
+	// IntentsExec grants executing commands, and forwarding ports, in the workload Pods.
+	IntentsExec   = "intents:exec"
+	ResourcesRead = "resources:read"
+	SecretsRead   = "secrets:read"
+	SecretsWrite  = "secrets:write"
+	TokensAdmin   = "tokens:admin"
+	// Admin grants any other permission.
+	Admin = "admin:*"
+)
+
+// All are the permissions the API tokens can be granted.
+var All = sets.New[string](IntentsRead, IntentsWrite, IntentsDelete, IntentsExec, ResourcesRead, SecretsRead, SecretsWrite, TokensAdmin, Admin)
+
+// Default returns the permissions of the tokens created with no requested ones.
+func Default() []string {
+	return []string{IntentsRead, IntentsWrite}
+}
+
+// Routes maps the API routes, as method and path template, to the permission they require:
+// the routes missing from the map require the Admin permission.
+var Routes = map[string]string{
+	"GET /auth/tokens":                    TokensAdmin,
+	"POST /auth/tokens":                   TokensAdmin,
+	"DELETE /auth/tokens/:token_id":       TokensAdmin,
+	"GET /intents":                        IntentsRead,
+	"POST /intents":                       IntentsWrite,
+	"DELETE /intents/:intent_id":          IntentsDelete,
+	"GET /intents/:intent_id":             IntentsRead,
+	"PATCH /intents/:intent_id":           IntentsWrite,
+	"GET /intents/:intent_id/events":      IntentsRead,
+	"GET /intents/:intent_id/exec":        IntentsExec,
+	"GET /intents/:intent_id/logs":        IntentsRead,
+	"GET /intents/:intent_id/portforward": IntentsExec,
+	"POST /intents/:intent_id/resume":     IntentsWrite,
+	"GET /resources":                      ResourcesRead,
+	"GET /secrets":                        SecretsRead,
+	"POST /secrets":                       SecretsWrite,
+	"DELETE /secrets/:secret_name":        SecretsWrite,
+}
+
+// Parse returns the permissions listed in the token annotation, comma separated.
+func Parse(value string) sets.Set[string] {
+	if value == "" {
+		return sets.New[string]()
+	}
+
+	return sets.New[string](strings.Split(value, ",")...)
+}
+
+// Format returns the permissions in the format of the token annotation.
+func Format(permissions sets.Set[string]) string {
+	return strings.Join(sets.List(permissions), ",")
+}
+
+// Grants returns true when the permissions grant the given one:
+// nil permissions stand for a token which is not restricted.
+func Grants(permissions sets.Set[string], permission string) bool {
+	return permissions == nil || permissions.Has(Admin) || permissions.Has(permission)
+}
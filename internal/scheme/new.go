@@ -7,6 +7,7 @@ import (
 	fluidosadvertisementv1alpha1 "github.com/fluidos-project/node/apis/advertisement/v1alpha1"
 	fluidosnodesv1alpha1 "github.com/fluidos-project/node/apis/nodecore/v1alpha1"
 	fluidosreservationv1alpha1 "github.com/fluidos-project/node/apis/reservation/v1alpha1"
+	liqoauthenticationv1beta1 "github.com/liqotech/liqo/apis/authentication/v1beta1"
 	liqooffloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
 	"github.com/pkg/errors"
 	capsulev1beta2 "github.com/projectcapsule/capsule/api/v1beta2"
@@ -47,5 +48,9 @@ func New() (*runtime.Scheme, error) {
 		return nil, errors.Wrap(err, "unable to register liqooffloadingv1beta1 Scheme")
 	}
 
+	if err := liqoauthenticationv1beta1.AddToScheme(scheme); err != nil {
+		return nil, errors.Wrap(err, "unable to register liqoauthenticationv1beta1 Scheme")
+	}
+
 	return scheme, nil
 }